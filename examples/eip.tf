@@ -16,3 +16,14 @@ resource "nifcloud_eip" "example_eip_kanri" {
   #instance          = "${nifcloud_instance.example_server_kanri[0].name}"
 }
 
+
+# EIP を別リソースで付け替える場合(nifcloud_eip 側の instance は指定しないこと)
+#resource "nifcloud_eip_association" "example_eip_association_cent" {
+#  public_ip = "${nifcloud_eip.example_eip_cent[0].public_ip}"
+#  instance  = "${nifcloud_instance.example_server_cent[0].name}"
+#}
+
+# 既存の EIP を参照する場合
+#data "nifcloud_eip" "example_eip_existing" {
+#  public_ip = "203.0.113.10"
+#}
@@ -14,8 +14,10 @@ resource "nifcloud_lb" "example_lb_web" {
 
   health_check {
     unhealthy_threshold = 1        # 1 - 10
-    target              = "TCP:80" # TCP:宛先ポート | ICMP
+    target              = "TCP:80" # TCP:宛先ポート | ICMP | HTTP(S):宛先ポート/パス
     interval            = "30"     # 5 - 300
+    #healthy_threshold  = 1  # 1 - 10
+    #timeout            = 5  # 2 - 60 (interval 未満)
   }
 
   #filter_type         = "1" # 1(許可する) | 2(拒否する)
@@ -51,8 +53,10 @@ resource "nifcloud_lb_port" "example_lb_web_443" {
 
   health_check {
     unhealthy_threshold = 1         # 1 - 10
-    target              = "TCP:443" # TCP:宛先ポート | ICMP
+    target              = "TCP:443" # TCP:宛先ポート | ICMP | HTTP(S):宛先ポート/パス
     interval            = "30"      # 5 - 300
+    #healthy_threshold  = 1  # 1 - 10
+    #timeout            = 5  # 2 - 60 (interval 未満)
   }
 
   #filter_type         = "1" # 1(許可する) | 2(拒否する)
@@ -75,3 +79,43 @@ resource "nifcloud_lb_port" "example_lb_web_443" {
   #  ignore_changes = [""]
   #}
 }
+
+# listener を複数指定する場合、instances / filter / health_check 等は listener ごとに指定する
+#resource "nifcloud_lb" "example_lb_multi" {
+#  name            = "multilb001"
+#  accounting_type = "${var.charge_type}"
+#  network_volume  = 10
+#
+#  listener {
+#    protocol       = "HTTP"
+#    lb_port        = 80
+#    instance_port  = 80
+#    balancing_type = "1"
+#
+#    health_check {
+#      unhealthy_threshold = 1
+#      target              = "TCP:80"
+#      interval            = "30"
+#    }
+#
+#    instances = ["${nifcloud_instance.example_server_cent[0].name}", "${nifcloud_instance.example_server_cent[1].name}"]
+#  }
+#
+#  listener {
+#    protocol       = "HTTPS"
+#    lb_port        = 443
+#    instance_port  = 443
+#    balancing_type = "1"
+#
+#    health_check {
+#      unhealthy_threshold = 1
+#      target              = "TCP:443"
+#      interval            = "30"
+#    }
+#
+#    filter_type         = "1"
+#    filter_ip_addresses = ["1.1.1.1", "1.1.1.2"]
+#
+#    instances = ["${nifcloud_instance.example_server_cent[0].name}"]
+#  }
+#}
@@ -1,7 +1,13 @@
 provider "nifcloud" {
   region = "${var.default_region}"
+  #shared_credentials_file = "~/.nifcloud/credentials"
+  #profile                 = "default"
+  #max_requests_per_second = 5
+  #default_description_prefix = "env:example "
 }
 
+data "nifcloud_caller_identity" "current" {}
+
 terraform {
   required_version = "<= 0.12.13"
 }
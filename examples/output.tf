@@ -24,3 +24,10 @@ output "puclic_eip_kanri" {
   value = "${nifcloud_eip.example_eip_kanri.*.public_ip}"
   #value = "${nifcloud_eip.example_eip_kanri.*.private_ip}"
 }
+#output "vpn_connection_001_rtx_config" {
+#  value     = "${nifcloud_vpn_connection.example_vpn_connection_001.customer_gateway_configuration["yamaha_rtx"]}" # cisco_ios | yamaha_rtx | strongswan
+#  sensitive = true
+#}
+output "caller_access_key" {
+  value = "${data.nifcloud_caller_identity.current.access_key}"
+}
@@ -14,6 +14,15 @@ resource "nifcloud_customer_gateway" "example_customer_gateway_001" {
 #  description         = "${lookup(var.customer_gateway_002, "memo")}"
 #}
 
+# 別のスタックで作成済みのゲートウェイを参照する場合 (customer_gateway_id / vpn_gateway_id, name, ip_address のいずれかで検索)
+#data "nifcloud_customer_gateway" "example_customer_gateway_001" {
+#  ip_address = "${var.customer_gateway_001["ip_address"]}"
+#}
+#
+#data "nifcloud_vpn_gateway" "example_vpn_gateway_001" {
+#  name = "${var.vpn_gateway_001["name"]}"
+#}
+
 resource "nifcloud_vpn_gateway" "example_vpn_gateway_001" {
   name               = "${lookup(var.vpn_gateway_001, "name")}"
   private_ip_address = "${lookup(var.vpn_gateway_001, "private_ip_address")}"
@@ -41,9 +50,6 @@ resource "nifcloud_vpn_connection" "example_vpn_connection_001" {
     pre_shared_key       = "${var.pre_shared_key_001}"
   }
   depends_on = ["nifcloud_vpn_gateway.example_vpn_gateway_001", "nifcloud_customer_gateway.example_customer_gateway_001"]
-  lifecycle {
-    ignore_changes = ["ipsec", "tunnel"]
-  }
 }
 
 #resource "nifcloud_vpn_connection" "example_vpn_connection_002" {
@@ -73,9 +79,6 @@ resource "nifcloud_vpn_connection" "example_vpn_connection_001" {
 #    source_port      = "1702" # modeがUnmanagedかつencapsulationがUDPの場合
 #  }
 #  #depends_on = ["nifcloud_vpn_gateway.example_vpn_gateway_001", "nifcloud_customer_gateway.example_customer_gateway_002"]
-#  lifecycle {
-#    ignore_changes = [ipsec, tunnel]
-#  }
 #}
 
 resource "nifcloud_route_table" "example_route_table_002" {}
@@ -15,6 +15,15 @@
 #    inout       = "IN"
 #  }
 #}
+#resource "nifcloud_securitygroup_ingress_rule" "example_firewallgroup_ingress_rule_001" {
+#  security_group_name = nifcloud_securitygroup.example_firewallgroup_001.name
+#  inout               = "IN"
+#  protocol            = "TCP"
+#  from_port           = 8443
+#  to_port             = 8443
+#  cidr_ip             = "0.0.0.0/0"
+#  description         = "tcp8443"
+#}
 #resource "nifcloud_securitygroup_rule" "example_firewallgroup_rule_002" {
 #  name = nifcloud_securitygroup.example_firewallgroup_001.name
 #  rules {
@@ -18,6 +18,7 @@ resource "nifcloud_router" "example_router_001" {
     #dhcp_config_id  = ""
   }
   #security_groups = [nifcloud_securitygroup.example_firewallgroup_003.name]
+  #reboot_mode     = "true" # force | true | false
   description = var.router_001["memo"]
 }
 
@@ -36,3 +37,8 @@ resource "nifcloud_route_table_association" "example_rta_001_forrouter001" {
   route_table_id = nifcloud_route_table.example_route_table_001.id
   depends_on     = [nifcloud_router.example_router_001]
 }
+
+# 既存のルーターを名前で参照する場合
+#data "nifcloud_router" "example_router_existing" {
+#  name = var.router_001["name"]
+#}
@@ -21,6 +21,8 @@ resource "nifcloud_instance" "example_server_cent" {
   security_groups   = [nifcloud_securitygroup.example_firewallgroup_004.name]
   description       = format("%s%03d", var.instance_cent["memo"], count.index + 1)
   user_data         = file(format("%s_%03d.sh", var.instance_cent["user_data"], count.index + 1))
+  #instance_state    = "running" # running | stopped
+  #reboot_on_change  = "true"    # force | true | false
   depends_on        = [nifcloud_network.example_privatelan_002]
 }
 
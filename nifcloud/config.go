@@ -2,6 +2,12 @@ package nifcloud
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
 	"github.com/shztki/nifcloud-sdk-go/nifcloud/credentials"
 	"github.com/shztki/nifcloud-sdk-go/nifcloud/session"
@@ -11,16 +17,36 @@ import (
 
 // Config is struct
 type Config struct {
-	AccessKey string
-	SecretKey string
-	Region    string
-	Endpoint  string
+	AccessKey             string
+	SecretKey             string
+	Region                string
+	Endpoint              string
+	Endpoints             map[string]string
+	SharedCredentialsFile string
+	Profile               string
+	MaxRetries            int
+	RetryBackoff          time.Duration
+	MaxRequestsPerSecond  int
+
+	DefaultDescriptionPrefix string
+
+	SkipCredentialsValidation bool
 }
 
 // NifcloudClient is struct
 type NifcloudClient struct {
 	computingconn *computing.Computing
 	rdbconn       *rdb.Rdb
+
+	session *session.Session
+
+	defaultDescriptionPrefix string
+
+	region            string
+	accessKey         string
+	credentialsSource string
+	computingEndpoint string
+	rdbEndpoint       string
 }
 
 // Client is function
@@ -29,28 +55,166 @@ func (c *Config) Client() (interface{}, error) {
 		return nil, fmt.Errorf("[Err] No Region Name for Nifcloud")
 	}
 
-	var credential *credentials.Credentials
-	if c.AccessKey != "" && c.SecretKey != "" {
-		credential = credentials.NewStaticCredentials(c.AccessKey, c.SecretKey, "")
-	} else {
-		credential = credentials.NewEnvCredentials()
+	// The legacy endpoint argument applies to every service unless overridden per service
+	overrides := map[string]string{}
+	if c.Endpoint != "" {
+		overrides[endpointsServiceComputing] = c.Endpoint
+		overrides[endpointsServiceRdb] = c.Endpoint
+	}
+	for k, v := range c.Endpoints {
+		if v != "" {
+			overrides[k] = v
+		}
+	}
+
+	computingEndpoint, err := resolveEndpoint(c.Region, endpointsServiceComputing, overrides)
+	if err != nil {
+		return nil, err
+	}
+	rdbEndpoint, err := resolveEndpoint(c.Region, endpointsServiceRdb, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := c.credentials()
+	if err != nil {
+		return nil, err
 	}
 
 	config := nifcloud.Config{
 		Region:      nifcloud.String(c.Region),
 		Credentials: credential,
+		Retryer:     newNifcloudRetryer(c.MaxRetries, c.RetryBackoff),
 	}
 
-	if c.Endpoint != "" {
-		config.WithEndpoint(*nifcloud.String(c.Endpoint))
+	sess := session.Must(session.NewSession(&config))
+
+	if c.MaxRequestsPerSecond > 0 {
+		sess.Handlers.Send.PushFrontNamed(newRequestRateLimiter(c.MaxRequestsPerSecond).handler())
 	}
 
-	sess := session.Must(session.NewSession(&config))
+	if logging.IsDebugOrHigher() {
+		sess.Handlers.Unmarshal.PushBackNamed(debugLogHandler())
+		sess.Handlers.UnmarshalError.PushBackNamed(debugLogHandler())
+	}
 
 	var client NifcloudClient
 
-	client.computingconn = computing.New(sess)
-	client.rdbconn = rdb.New(sess)
+	client.computingconn = computing.New(sess, nifcloud.NewConfig().WithEndpoint(computingEndpoint))
+	client.session = sess
+	client.rdbconn = rdb.New(sess, nifcloud.NewConfig().WithEndpoint(rdbEndpoint))
+	client.defaultDescriptionPrefix = c.DefaultDescriptionPrefix
+
+	value, err := credential.Get()
+	if err != nil {
+		return nil, err
+	}
+	client.region = c.Region
+	client.accessKey = value.AccessKeyID
+	client.credentialsSource = value.ProviderName
+	client.computingEndpoint = computingEndpoint
+	client.rdbEndpoint = rdbEndpoint
+
+	if !c.SkipCredentialsValidation {
+		if err := client.validateCredentials(); err != nil {
+			return nil, err
+		}
+	}
 
 	return &client, nil
 }
+
+// computingConnForRegion returns a computing client for region, sharing the
+// provider's credentials and handlers. The provider region keeps its own client,
+// endpoint overrides only apply there.
+func (client *NifcloudClient) computingConnForRegion(region string) (*computing.Computing, error) {
+	if region == "" || region == client.region {
+		return client.computingconn, nil
+	}
+
+	endpoint, err := resolveEndpoint(region, endpointsServiceComputing, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return computing.New(client.session, nifcloud.NewConfig().WithEndpoint(endpoint).WithRegion(region)), nil
+}
+
+// validateCredentials makes one cheap API call, so that wrong keys are reported once
+// by the provider instead of by the first resource
+func (client *NifcloudClient) validateCredentials() error {
+	if _, err := client.computingconn.DescribeRegions(&computing.DescribeRegionsInput{}); err != nil {
+		return fmt.Errorf("[Err] Nifcloud rejected the credentials (access key %s from %s, region %s, endpoint %s): %s",
+			client.accessKey, client.credentialsSource, client.region, client.computingEndpoint, err)
+	}
+	return nil
+}
+
+// credentials resolves the keys in order of static keys, environment variables and the shared credentials file
+func (c *Config) credentials() (*credentials.Credentials, error) {
+	credential := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.StaticProvider{Value: credentials.Value{
+			AccessKeyID:     c.AccessKey,
+			SecretAccessKey: c.SecretKey,
+		}},
+		&credentials.EnvProvider{},
+		&sharedCredentialsProvider{
+			Filename: c.SharedCredentialsFile,
+			Profile:  c.Profile,
+		},
+	})
+
+	if _, err := credential.Get(); err != nil {
+		filename := c.SharedCredentialsFile
+		if filename == "" {
+			filename = "~/.nifcloud/credentials"
+		}
+		return nil, fmt.Errorf("[Err] No valid credential sources found for Nifcloud (static keys, environment variables or %s): %s", filename, err)
+	}
+
+	return credential, nil
+}
+
+// sharedCredentialsProvider finds the shared credentials file only once the
+// chain gets to it, so a missing home directory doesn't matter with static
+// or environment keys.
+type sharedCredentialsProvider struct {
+	Filename string
+	Profile  string
+
+	provider *credentials.SharedCredentialsProvider
+}
+
+func (p *sharedCredentialsProvider) Retrieve() (credentials.Value, error) {
+	filename, err := sharedCredentialsFilename(p.Filename)
+	if err != nil {
+		return credentials.Value{ProviderName: credentials.SharedCredsProviderName}, err
+	}
+
+	p.provider = &credentials.SharedCredentialsProvider{
+		Filename: filename,
+		Profile:  p.Profile,
+	}
+	return p.provider.Retrieve()
+}
+
+func (p *sharedCredentialsProvider) IsExpired() bool {
+	return p.provider == nil || p.provider.IsExpired()
+}
+
+// sharedCredentialsFilename defaults to ~/.nifcloud/credentials and expands a leading ~
+func sharedCredentialsFilename(filename string) (string, error) {
+	if filename != "" && !strings.HasPrefix(filename, "~") {
+		return filename, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("[Err] Unable to find home directory for shared credentials file: %s", err)
+	}
+
+	if filename == "" {
+		return filepath.Join(home, ".nifcloud", "credentials"), nil
+	}
+	return filepath.Join(home, strings.TrimPrefix(filename, "~")), nil
+}
@@ -0,0 +1,53 @@
+package nifcloud
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceNifcloudCallerIdentity() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNifcloudCallerIdentityRead,
+
+		Schema: map[string]*schema.Schema{
+			"access_key": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"credentials_source": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"region": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"computing_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"rdb_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceNifcloudCallerIdentityRead checks the credentials again, even with skip_credentials_validation,
+// and returns what the provider was configured with
+func dataSourceNifcloudCallerIdentityRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*NifcloudClient)
+
+	if err := client.validateCredentials(); err != nil {
+		return err
+	}
+
+	d.SetId(client.accessKey)
+	d.Set("access_key", client.accessKey)
+	d.Set("credentials_source", client.credentialsSource)
+	d.Set("region", client.region)
+	d.Set("computing_endpoint", client.computingEndpoint)
+	d.Set("rdb_endpoint", client.rdbEndpoint)
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package nifcloud
+
+import (
+	"fmt"
+
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceNifcloudCustomerGateway() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNifcloudCustomerGatewayRead,
+
+		Schema: map[string]*schema.Schema{
+			"customer_gateway_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"ip_address": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"lan_side_ip_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"lan_side_cidr_block": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceNifcloudCustomerGatewayRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	filters := make([]*computing.RequestFilterStruct, 0, 3)
+	if v, ok := d.GetOk("customer_gateway_id"); ok {
+		filters = append(filters, &computing.RequestFilterStruct{
+			Name:         nifcloud.String("customer-gateway-id"),
+			RequestValue: []*string{nifcloud.String(v.(string))},
+		})
+	}
+	if v, ok := d.GetOk("name"); ok {
+		filters = append(filters, &computing.RequestFilterStruct{
+			Name:         nifcloud.String("nifty-customer-gateway-name"),
+			RequestValue: []*string{nifcloud.String(v.(string))},
+		})
+	}
+	if v, ok := d.GetOk("ip_address"); ok {
+		filters = append(filters, &computing.RequestFilterStruct{
+			Name:         nifcloud.String("ip-address"),
+			RequestValue: []*string{nifcloud.String(v.(string))},
+		})
+	}
+	if len(filters) == 0 {
+		return fmt.Errorf("One of customer_gateway_id, name or ip_address must be set")
+	}
+
+	resp, err := conn.DescribeCustomerGateways(&computing.DescribeCustomerGatewaysInput{
+		Filter: filters,
+	})
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.CustomerGatewayId") {
+			return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+		}
+		return fmt.Errorf("Error finding CustomerGateway: %s", err)
+	}
+
+	gateways := make([]*computing.CustomerGatewaySetItem, 0, len(resp.CustomerGatewaySet))
+	for _, gateway := range resp.CustomerGatewaySet {
+		if nifcloud.StringValue(gateway.State) != "deleted" {
+			gateways = append(gateways, gateway)
+		}
+	}
+	if len(gateways) == 0 {
+		return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+	}
+	if len(gateways) > 1 {
+		return fmt.Errorf("Your query returned more than one result. Please try a more specific search criteria.")
+	}
+
+	customerGateway := gateways[0]
+	d.SetId(*customerGateway.CustomerGatewayId)
+	d.Set("customer_gateway_id", customerGateway.CustomerGatewayId)
+	d.Set("name", customerGateway.NiftyCustomerGatewayName)
+	d.Set("ip_address", customerGateway.IpAddress)
+	d.Set("description", customerGateway.NiftyCustomerGatewayDescription)
+	d.Set("lan_side_cidr_block", customerGateway.NiftyLanSideCidrBlock)
+	d.Set("lan_side_ip_address", customerGateway.NiftyLanSideIpAddress)
+	d.Set("state", customerGateway.State)
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package nifcloud
+
+import (
+	"fmt"
+
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceNifcloudEip() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNifcloudEipRead,
+
+		Schema: map[string]*schema.Schema{
+			"public_ip": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"private_ip"},
+			},
+			"private_ip": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"public_ip"},
+			},
+			"instance": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"nifty_private_ip": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceNifcloudEipRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	req := &computing.DescribeAddressesInput{}
+	if v, ok := d.GetOk("public_ip"); ok {
+		req = describeAddressesInput(v.(string), false)
+	} else if v, ok := d.GetOk("private_ip"); ok {
+		req = describeAddressesInput(v.(string), true)
+	} else if _, ok := d.GetOk("instance"); !ok {
+		return fmt.Errorf("One of public_ip, private_ip or instance must be set")
+	}
+
+	resp, err := conn.DescribeAddresses(req)
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.IpAddress") {
+			return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+		}
+		return fmt.Errorf("Error finding EIP: %s", err)
+	}
+
+	addresses := make([]*computing.AddressesSetItem, 0, len(resp.AddressesSet))
+	for _, address := range resp.AddressesSet {
+		if v, ok := d.GetOk("public_ip"); ok && nifcloud.StringValue(address.PublicIp) != v.(string) {
+			continue
+		}
+		if v, ok := d.GetOk("private_ip"); ok && nifcloud.StringValue(address.PrivateIpAddress) != v.(string) {
+			continue
+		}
+		if v, ok := d.GetOk("instance"); ok && nifcloud.StringValue(address.InstanceId) != v.(string) {
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	if len(addresses) == 0 {
+		return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+	}
+	if len(addresses) > 1 {
+		return fmt.Errorf("Your query returned more than one result. Please try a more specific search criteria.")
+	}
+
+	address := addresses[0]
+	ip := nifcloud.StringValue(address.PublicIp)
+	if ip == "" {
+		ip = nifcloud.StringValue(address.PrivateIpAddress)
+	}
+
+	// told apart the same way as the imports of nifcloud_eip and nifcloud_eip_association
+	_, private, err := findAddressByIP(conn, ip)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(ip)
+	d.Set("public_ip", address.PublicIp)
+	d.Set("private_ip", address.PrivateIpAddress)
+	d.Set("instance", address.InstanceId)
+	d.Set("nifty_private_ip", private)
+	d.Set("availability_zone", address.AvailabilityZone)
+	d.Set("description", address.Description)
+
+	return nil
+}
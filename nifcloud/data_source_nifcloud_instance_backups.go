@@ -0,0 +1,130 @@
+package nifcloud
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceNifcloudInstanceBackups() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNifcloudInstanceBackupsRead,
+
+		Schema: map[string]*schema.Schema{
+			"instance_backup_rule_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rules": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance_backup_rule_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"instance_unique_id": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"backups": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"backup_instance_id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"create_time": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceNifcloudInstanceBackupsRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	input := computing.DescribeInstanceBackupRulesInput{}
+	if v, ok := d.GetOk("instance_backup_rule_id"); ok {
+		input.InstanceBackupRuleId = []*string{nifcloud.String(v.(string))}
+	}
+
+	out, err := conn.DescribeInstanceBackupRules(&input)
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.InstanceBackupRuleId") {
+			return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+		}
+		return fmt.Errorf("Error finding InstanceBackupRule: %s", err)
+	}
+
+	rules := make([]map[string]interface{}, 0, len(out.InstanceBackupRulesSet))
+	for _, rule := range out.InstanceBackupRulesSet {
+		if v, ok := d.GetOk("name"); ok && nifcloud.StringValue(rule.InstanceBackupRuleName) != v.(string) {
+			continue
+		}
+
+		instanceUniqueIDs := make([]string, 0, len(rule.InstancesSet))
+		for _, instance := range rule.InstancesSet {
+			instanceUniqueIDs = append(instanceUniqueIDs, nifcloud.StringValue(instance.InstanceUniqueId))
+		}
+
+		backups := make([]map[string]interface{}, 0, len(rule.InstanceBackupsSet))
+		for _, backup := range rule.InstanceBackupsSet {
+			createTime := ""
+			if backup.InstanceBackupInstanceCreateTime != nil {
+				createTime = backup.InstanceBackupInstanceCreateTime.Format(time.RFC3339)
+			}
+			backups = append(backups, map[string]interface{}{
+				"backup_instance_id": nifcloud.StringValue(backup.InstanceBackupInstanceId),
+				"create_time":        createTime,
+			})
+		}
+
+		rules = append(rules, map[string]interface{}{
+			"instance_backup_rule_id": nifcloud.StringValue(rule.InstanceBackupRuleId),
+			"name":                    nifcloud.StringValue(rule.InstanceBackupRuleName),
+			"status":                  nifcloud.StringValue(rule.InstanceBackupRuleStatus),
+			"instance_unique_id":      instanceUniqueIDs,
+			"backups":                 backups,
+		})
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+	}
+
+	// The same query keeps the same ID
+	d.SetId(fmt.Sprintf("%d", hashcode.String(d.Get("instance_backup_rule_id").(string)+"/"+d.Get("name").(string))))
+	if err := d.Set("rules", rules); err != nil {
+		return fmt.Errorf("Error setting rules: %s", err)
+	}
+
+	return nil
+}
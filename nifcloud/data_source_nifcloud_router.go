@@ -0,0 +1,135 @@
+package nifcloud
+
+import (
+	"fmt"
+
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceNifcloudRouter() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNifcloudRouterRead,
+
+		Schema: map[string]*schema.Schema{
+			"router_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"router_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"accounting_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"security_groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"route_table_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"route_table_association_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"nat_table_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"nat_table_association_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"network_interface_status": routerNetworkInterfaceStatusSchema(),
+		},
+	}
+}
+
+func dataSourceNifcloudRouterRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	filters := make([]*computing.RequestFilterStruct, 0, 2)
+	if v, ok := d.GetOk("router_id"); ok {
+		filters = append(filters, &computing.RequestFilterStruct{
+			Name:         nifcloud.String("router-id"),
+			RequestValue: []*string{nifcloud.String(v.(string))},
+		})
+	}
+	if v, ok := d.GetOk("name"); ok {
+		filters = append(filters, &computing.RequestFilterStruct{
+			Name:         nifcloud.String("router-name"),
+			RequestValue: []*string{nifcloud.String(v.(string))},
+		})
+	}
+	if len(filters) == 0 {
+		return fmt.Errorf("One of router_id or name must be set")
+	}
+
+	resp, err := conn.NiftyDescribeRouters(&computing.NiftyDescribeRoutersInput{
+		Filter: filters,
+	})
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouterId") {
+			return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+		}
+		return fmt.Errorf("Error finding router: %s", err)
+	}
+
+	routers := make([]*computing.RouterSetItem, 0, len(resp.RouterSet))
+	for _, router := range resp.RouterSet {
+		if nifcloud.StringValue(router.State) != "deleted" {
+			routers = append(routers, router)
+		}
+	}
+	if len(routers) == 0 {
+		return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+	}
+	if len(routers) > 1 {
+		return fmt.Errorf("Your query returned more than one result. Please try a more specific search criteria.")
+	}
+
+	router := routers[0]
+	d.SetId(*router.RouterId)
+	d.Set("router_id", router.RouterId)
+	d.Set("name", router.RouterName)
+	d.Set("availability_zone", router.AvailabilityZone)
+	d.Set("router_type", router.Type)
+	d.Set("accounting_type", router.NextMonthAccountingType)
+	d.Set("description", router.Description)
+	d.Set("state", router.State)
+
+	sgs := make([]string, 0, len(router.GroupSet))
+	for _, sg := range router.GroupSet {
+		sgs = append(sgs, *sg.GroupId)
+	}
+	if err := d.Set("security_groups", sgs); err != nil {
+		return err
+	}
+
+	return setNifcloudRouterAttachments(d, router)
+}
@@ -0,0 +1,156 @@
+package nifcloud
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceNifcloudVpnGateway() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNifcloudVpnGatewayRead,
+
+		Schema: map[string]*schema.Schema{
+			"vpn_gateway_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"ip_address": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vpn_gateway_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"accounting_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"private_ip_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"network_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"network_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"security_groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceNifcloudVpnGatewayRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	filters := make([]*computing.RequestFilterStruct, 0, 3)
+	if v, ok := d.GetOk("vpn_gateway_id"); ok {
+		filters = append(filters, &computing.RequestFilterStruct{
+			Name:         nifcloud.String("vpn-gateway-id"),
+			RequestValue: []*string{nifcloud.String(v.(string))},
+		})
+	}
+	if v, ok := d.GetOk("name"); ok {
+		filters = append(filters, &computing.RequestFilterStruct{
+			Name:         nifcloud.String("nifty-vpn-gateway-name"),
+			RequestValue: []*string{nifcloud.String(v.(string))},
+		})
+	}
+	if v, ok := d.GetOk("ip_address"); ok {
+		filters = append(filters, &computing.RequestFilterStruct{
+			Name:         nifcloud.String("ip-address"),
+			RequestValue: []*string{nifcloud.String(v.(string))},
+		})
+	}
+	if len(filters) == 0 {
+		return fmt.Errorf("One of vpn_gateway_id, name or ip_address must be set")
+	}
+
+	resp, err := conn.DescribeVpnGateways(&computing.DescribeVpnGatewaysInput{
+		Filter: filters,
+	})
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnGatewayId") {
+			return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+		}
+		return fmt.Errorf("Error finding VpnGateway: %s", err)
+	}
+
+	gateways := make([]*computing.VpnGatewaySetItem, 0, len(resp.VpnGatewaySet))
+	for _, gateway := range resp.VpnGatewaySet {
+		if nifcloud.StringValue(gateway.State) != "deleted" {
+			gateways = append(gateways, gateway)
+		}
+	}
+	if len(gateways) == 0 {
+		return fmt.Errorf("Your query returned no results. Please change your search criteria and try again.")
+	}
+	if len(gateways) > 1 {
+		return fmt.Errorf("Your query returned more than one result. Please try a more specific search criteria.")
+	}
+
+	vpnGateway := gateways[0]
+	d.SetId(*vpnGateway.VpnGatewayId)
+	d.Set("vpn_gateway_id", vpnGateway.VpnGatewayId)
+	d.Set("name", vpnGateway.NiftyVpnGatewayName)
+	d.Set("ip_address", vpnGateway.IpAddress)
+	d.Set("description", vpnGateway.NiftyVpnGatewayDescription)
+	d.Set("vpn_gateway_type", vpnGateway.NiftyVpnGatewayType)
+	d.Set("accounting_type", vpnGateway.NextMonthAccountingType)
+	d.Set("availability_zone", vpnGateway.AvailabilityZone)
+	d.Set("state", vpnGateway.State)
+
+	// The private side is the interface that isn't on the common global network
+	for _, ni := range vpnGateway.NetworkInterfaceSet {
+		if nifcloud.StringValue(ni.NetworkId) == "net-COMMON_GLOBAL" {
+			continue
+		}
+		d.Set("private_ip_address", ni.IpAddress)
+		d.Set("network_id", ni.NetworkId)
+		d.Set("network_name", ni.NetworkName)
+		break
+	}
+
+	sgs := make([]string, 0, len(vpnGateway.GroupSet))
+	for _, sg := range vpnGateway.GroupSet {
+		sgs = append(sgs, *sg.GroupId)
+	}
+
+	log.Printf("[DEBUG] Setting Security Group Ids: %#v", sgs)
+	if err := d.Set("security_groups", sgs); err != nil {
+		return err
+	}
+
+	return nil
+}
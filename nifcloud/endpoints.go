@@ -0,0 +1,52 @@
+package nifcloud
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Services with their own API endpoint per region
+const (
+	endpointsServiceComputing = "computing"
+	endpointsServiceRdb       = "rdb"
+	endpointsServiceNas       = "nas"
+)
+
+// nifcloudRegions is the built-in region table, service name to endpoint
+var nifcloudRegions = map[string]map[string]string{
+	"jp-east-1": nifcloudRegionEndpoints("jp-east-1"),
+	"jp-east-2": nifcloudRegionEndpoints("jp-east-2"),
+	"jp-east-3": nifcloudRegionEndpoints("jp-east-3"),
+	"jp-east-4": nifcloudRegionEndpoints("jp-east-4"),
+	"jp-west-1": nifcloudRegionEndpoints("jp-west-1"),
+	"us-east-1": nifcloudRegionEndpoints("us-east-1"),
+}
+
+func nifcloudRegionEndpoints(region string) map[string]string {
+	return map[string]string{
+		endpointsServiceComputing: fmt.Sprintf("https://%s.computing.api.nifcloud.com/api/", region),
+		endpointsServiceRdb:       fmt.Sprintf("https://%s.rdb.api.nifcloud.com/", region),
+		endpointsServiceNas:       fmt.Sprintf("https://%s.nas.api.nifcloud.com/", region),
+	}
+}
+
+// resolveEndpoint returns the endpoint of service in region. An override wins over the region table,
+// so a region missing from the table is fine as long as the service is overridden.
+func resolveEndpoint(region, service string, overrides map[string]string) (string, error) {
+	if v := overrides[service]; v != "" {
+		return v, nil
+	}
+
+	endpoints, ok := nifcloudRegions[region]
+	if !ok {
+		regions := make([]string, 0, len(nifcloudRegions))
+		for r := range nifcloudRegions {
+			regions = append(regions, r)
+		}
+		sort.Strings(regions)
+		return "", fmt.Errorf("[Err] Unknown region %q for Nifcloud, expected one of %s", region, strings.Join(regions, ", "))
+	}
+
+	return endpoints[service], nil
+}
@@ -0,0 +1,166 @@
+package nifcloud
+
+import (
+	"encoding/json"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
+	"github.com/shztki/nifcloud-sdk-go/nifcloud/client"
+	"github.com/shztki/nifcloud-sdk-go/nifcloud/request"
+)
+
+// maxRetryBackoff caps the delay between two attempts
+const maxRetryBackoff = 60 * time.Second
+
+// nifcloudRetryer retries what the SDK retries by default and the NIFCLOUD throttling
+// errors on top, backing off exponentially from the configured retry_backoff.
+type nifcloudRetryer struct {
+	client.DefaultRetryer
+	backoff time.Duration
+}
+
+func newNifcloudRetryer(maxRetries int, backoff time.Duration) nifcloudRetryer {
+	return nifcloudRetryer{
+		DefaultRetryer: client.DefaultRetryer{NumMaxRetries: maxRetries},
+		backoff:        backoff,
+	}
+}
+
+// ShouldRetry implements request.Retryer
+func (r nifcloudRetryer) ShouldRetry(req *request.Request) bool {
+	if isNifcloudThrottledErr(req.Error) {
+		return true
+	}
+	return r.DefaultRetryer.ShouldRetry(req)
+}
+
+// RetryRules implements request.Retryer
+func (r nifcloudRetryer) RetryRules(req *request.Request) time.Duration {
+	if r.backoff <= 0 {
+		return r.DefaultRetryer.RetryRules(req)
+	}
+
+	retryCount := req.RetryCount
+	if retryCount > 10 {
+		retryCount = 10
+	}
+
+	delay := r.backoff*time.Duration(1<<uint(retryCount)) + time.Duration(rand.Int63n(int64(r.backoff)))
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
+
+// requestRateLimiter spaces requests out to at most one per interval, across all goroutines
+type requestRateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRequestRateLimiter(maxRequestsPerSecond int) *requestRateLimiter {
+	return &requestRateLimiter{
+		interval: time.Second / time.Duration(maxRequestsPerSecond),
+	}
+}
+
+func (l *requestRateLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(wait)
+}
+
+// handler returns the Send handler which holds every attempt, retries included, until its slot
+func (l *requestRateLimiter) handler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "nifcloud.RequestRateLimiter",
+		Fn: func(r *request.Request) {
+			l.wait()
+		},
+	}
+}
+
+// redactedValue replaces the value of every sensitive parameter in the debug log
+const redactedValue = "********"
+
+// sensitiveParams are the (lower cased) parameter names never written to the log as is.
+// Anything containing "password" is masked as well.
+var sensitiveParams = map[string]bool{
+	"admin":           true,
+	"presharedkey":    true,
+	"secretaccesskey": true,
+	"userdata":        true,
+}
+
+// debugLogHandler returns the handler logging every API call once its response is unmarshaled,
+// with the action, parameters, retries and elapsed time. Add it to Unmarshal and UnmarshalError.
+func debugLogHandler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "nifcloud.DebugLogHandler",
+		Fn: func(r *request.Request) {
+			result := "OK"
+			if r.Error != nil {
+				result = r.Error.Error()
+				if awsErr, ok := r.Error.(awserr.Error); ok {
+					result = awsErr.Code()
+				}
+			}
+
+			log.Printf("[DEBUG] NIFCLOUD %s/%s: %s (retries %d, %s) params: %s",
+				r.ClientInfo.ServiceName, r.Operation.Name, result,
+				r.RetryCount, time.Since(r.Time).Round(time.Millisecond), redactParams(r.Params))
+		},
+	}
+}
+
+// redactParams renders the request parameters as JSON with the sensitive ones masked
+func redactParams(params interface{}) string {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return "(unavailable)"
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "(unavailable)"
+	}
+
+	b, err = json.Marshal(redactValue(v))
+	if err != nil {
+		return "(unavailable)"
+	}
+	return string(b)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			name := strings.ToLower(k)
+			if sensitiveParams[name] || strings.Contains(name, "password") {
+				if e != nil {
+					t[k] = redactedValue
+				}
+				continue
+			}
+			t[k] = redactValue(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactValue(e)
+		}
+	}
+	return v
+}
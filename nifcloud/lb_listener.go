@@ -0,0 +1,943 @@
+package nifcloud
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Per-listener settings shared by nifcloud_lb and nifcloud_lb_port.
+// With a single listener they are top-level arguments, with several
+// listeners on nifcloud_lb they live inside each listener block.
+var lbListenerSettingKeys = []string{
+	"instances",
+	"filter_type",
+	"filter_ip_addresses",
+	"session_stickiness_policy_enable",
+	"session_stickiness_policy_expiration_period",
+	"sorry_page_enable",
+	"sorry_page_status_code",
+	"ssl_certificate_id",
+	"ssl_policy_id",
+	"health_check",
+}
+
+// lbListenerSettingsSchema returns the per-listener settings schema.
+// topLevel marks the single listener form where filter_type and health_check
+// are filled from the API.
+func lbListenerSettingsSchema(topLevel bool) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"instances": {
+			Type:     schema.TypeSet,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Optional: true,
+			Set:      schema.HashString,
+		},
+
+		"filter_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"1", "2"}, true),
+		},
+
+		"filter_ip_addresses": {
+			Type:     schema.TypeSet,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			MaxItems: 10,
+			Optional: true,
+			Set:      schema.HashString,
+		},
+
+		"session_stickiness_policy_enable": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+
+		"session_stickiness_policy_expiration_period": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(3, 60),
+		},
+
+		"sorry_page_enable": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+
+		"sorry_page_status_code": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntInSlice([]int{200, 503}),
+		},
+
+		"ssl_certificate_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+
+		"ssl_policy_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+
+		"health_check": lbHealthCheckSchema(topLevel),
+	}
+
+	// nested blocks can't be Computed, setNifcloudLbListeners keeps them to
+	// what the config holds instead
+	if topLevel {
+		s["filter_type"].Computed = true
+	}
+
+	return s
+}
+
+func lbHealthCheckSchema(computed bool) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: computed,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"healthy_threshold": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Computed:     computed,
+					ValidateFunc: validation.IntBetween(1, 10),
+				},
+
+				"unhealthy_threshold": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntBetween(1, 10),
+				},
+
+				"target": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateHeathCheckTarget,
+				},
+
+				"interval": {
+					Type:         schema.TypeInt,
+					Required:     true,
+					ValidateFunc: validation.IntBetween(5, 300),
+				},
+
+				"timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Computed:     computed,
+					ValidateFunc: validation.IntBetween(2, 60),
+				},
+			},
+		},
+	}
+}
+
+// lbListenerSchema returns the listener block schema.
+// maxItems 0 means unlimited, in which case each block carries its own settings.
+func lbListenerSchema(maxItems int) *schema.Schema {
+	elem := map[string]*schema.Schema{
+		"protocol": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateListenerProtocol(),
+		},
+
+		"lb_port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(1, 65535),
+		},
+
+		"instance_port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntBetween(1, 65535),
+		},
+
+		"balancing_type": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      1,
+			ValidateFunc: validation.IntInSlice([]int{1, 2}),
+		},
+	}
+
+	if maxItems != 1 {
+		for k, v := range lbListenerSettingsSchema(false) {
+			elem[k] = v
+		}
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Required: true,
+		MinItems: 1,
+		MaxItems: maxItems,
+		Elem: &schema.Resource{
+			Schema: elem,
+		},
+	}
+}
+
+// lbListenerPorts returns the ports of a listener block, falling back to the
+// well-known port of the protocol when they are omitted.
+func lbListenerPorts(data map[string]interface{}) (int64, int64) {
+	defaultPort := map[string]int64{
+		"HTTP":  80,
+		"HTTPS": 443,
+		"FTP":   21,
+	}[data["protocol"].(string)]
+
+	lbPort := int64(data["lb_port"].(int))
+	if lbPort == 0 {
+		lbPort = defaultPort
+	}
+	instancePort := int64(data["instance_port"].(int))
+	if instancePort == 0 {
+		instancePort = defaultPort
+	}
+
+	return lbPort, instancePort
+}
+
+func lbListenerKey(data map[string]interface{}) string {
+	lbPort, instancePort := lbListenerPorts(data)
+	return fmt.Sprintf("%d-%d", lbPort, instancePort)
+}
+
+// lbListenerBaseChanged reports whether protocol, ports or balancing type differ.
+func lbListenerBaseChanged(o, n map[string]interface{}) bool {
+	for _, k := range []string{"protocol", "lb_port", "instance_port", "balancing_type"} {
+		if o[k] != n[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// lbListener is one port pair of a load balancer, which is the unit
+// every listener level API of NIFCLOUD works on.
+type lbListener struct {
+	conn             *computing.Computing
+	name             string
+	loadBalancerPort *int64
+	instancePort     *int64
+	timeout          time.Duration
+}
+
+func newLbListener(conn *computing.Computing, name string, data map[string]interface{}, timeout time.Duration) *lbListener {
+	lbPort, instancePort := lbListenerPorts(data)
+	return &lbListener{
+		conn:             conn,
+		name:             name,
+		loadBalancerPort: nifcloud.Int64(lbPort),
+		instancePort:     nifcloud.Int64(instancePort),
+		timeout:          timeout,
+	}
+}
+
+func (l *lbListener) String() string {
+	return fmt.Sprintf("%s(%d:%d)", l.name, *l.loadBalancerPort, *l.instancePort)
+}
+
+func (l *lbListener) register(data map[string]interface{}) error {
+	listeners, err := expandListeners([]interface{}{data})
+	if err != nil {
+		return err
+	}
+
+	input := &computing.RegisterPortWithLoadBalancerInput{
+		LoadBalancerName: nifcloud.String(l.name),
+		Listeners:        listeners,
+	}
+
+	if _, err := l.conn.RegisterPortWithLoadBalancer(input); err != nil {
+		return fmt.Errorf("Error adding port LB %s: %s", l, err)
+	}
+
+	return nil
+}
+
+func (l *lbListener) update(data map[string]interface{}) error {
+	listener, err := expandListener([]interface{}{data})
+	if err != nil {
+		return err
+	}
+
+	req := &computing.UpdateLoadBalancerInput{
+		LoadBalancerName: nifcloud.String(l.name),
+		ListenerUpdate: &computing.RequestListenerUpdateStruct{
+			InstancePort:          l.instancePort,
+			LoadBalancerPort:      l.loadBalancerPort,
+			RequestListenerStruct: listener,
+		},
+	}
+
+	log.Printf("[INFO] Updating LoadBalancer listener %s", l)
+	if err := updateNifcloudLoadBalancer(l.conn, req, l.timeout); err != nil {
+		return fmt.Errorf("Error updating LoadBalancer listener %s: %s", l, err)
+	}
+
+	lbPort, instancePort := lbListenerPorts(data)
+	l.loadBalancerPort = nifcloud.Int64(lbPort)
+	l.instancePort = nifcloud.Int64(instancePort)
+
+	return nil
+}
+
+// delete removes the port from the load balancer, the load balancer itself
+// goes away together with its last port.
+func (l *lbListener) delete() error {
+	log.Printf("[INFO] Deleting LB: %s", l)
+
+	input := &computing.DeleteLoadBalancerInput{
+		LoadBalancerName: nifcloud.String(l.name),
+		InstancePort:     l.instancePort,
+		LoadBalancerPort: l.loadBalancerPort,
+	}
+	if _, err := l.conn.DeleteLoadBalancer(input); err != nil {
+		if isLoadBalancerNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("Error deleting LB %s: %s", l, err)
+	}
+
+	return nil
+}
+
+// applySettings moves the listener from the o settings to the n settings.
+// o is empty for a listener that was just created.
+func (l *lbListener) applySettings(o, n map[string]interface{}) error {
+	if !reflect.DeepEqual(lbSettingList(o, "health_check"), lbSettingList(n, "health_check")) {
+		if err := l.configureHealthCheck(lbSettingList(n, "health_check")); err != nil {
+			return err
+		}
+	}
+
+	if err := l.updateInstances(lbSettingSet(o, "instances"), lbSettingSet(n, "instances")); err != nil {
+		return err
+	}
+
+	if err := l.updateFilter(o, n); err != nil {
+		return err
+	}
+
+	if lbSettingBool(o, "session_stickiness_policy_enable") != lbSettingBool(n, "session_stickiness_policy_enable") ||
+		lbSettingInt(o, "session_stickiness_policy_expiration_period") != lbSettingInt(n, "session_stickiness_policy_expiration_period") {
+		opts := &computing.RequestSessionStickinessPolicyUpdateStruct{
+			Enable: nifcloud.Bool(lbSettingBool(n, "session_stickiness_policy_enable")),
+		}
+		if v := lbSettingInt(n, "session_stickiness_policy_expiration_period"); v > 0 {
+			opts.ExpirationPeriod = nifcloud.Int64(int64(v))
+		}
+		if err := l.updateOption(&computing.UpdateLoadBalancerOptionInput{SessionStickinessPolicyUpdate: opts}); err != nil {
+			return err
+		}
+	}
+
+	if lbSettingBool(o, "sorry_page_enable") != lbSettingBool(n, "sorry_page_enable") ||
+		lbSettingInt(o, "sorry_page_status_code") != lbSettingInt(n, "sorry_page_status_code") {
+		opts := &computing.RequestSorryPageUpdateStruct{
+			Enable: nifcloud.Bool(lbSettingBool(n, "sorry_page_enable")),
+		}
+		if v := lbSettingInt(n, "sorry_page_status_code"); v > 0 {
+			opts.StatusCode = nifcloud.Int64(int64(v))
+		}
+		if err := l.updateOption(&computing.UpdateLoadBalancerOptionInput{SorryPageUpdate: opts}); err != nil {
+			return err
+		}
+	}
+
+	if lbSettingString(o, "ssl_certificate_id") != lbSettingString(n, "ssl_certificate_id") {
+		if err := l.setSSLCertificate(lbSettingString(n, "ssl_certificate_id")); err != nil {
+			return err
+		}
+	}
+
+	if lbSettingString(o, "ssl_policy_id") != lbSettingString(n, "ssl_policy_id") {
+		if err := l.setSSLPolicy(lbSettingString(n, "ssl_policy_id")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *lbListener) configureHealthCheck(hc []interface{}) error {
+	if len(hc) == 0 || hc[0] == nil {
+		return nil
+	}
+
+	log.Printf("[INFO] Updating HealthCheck %s", l)
+	healthCheck, err := expandHealthCheck(hc[0].(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+
+	_, err = l.conn.ConfigureHealthCheck(&computing.ConfigureHealthCheckInput{
+		LoadBalancerName: nifcloud.String(l.name),
+		InstancePort:     l.instancePort,
+		LoadBalancerPort: l.loadBalancerPort,
+		HealthCheck:      healthCheck,
+	})
+	if err != nil {
+		return fmt.Errorf("Failure configuring health check for LB: %s", err)
+	}
+
+	return nil
+}
+
+// If we currently have instances, or did have instances,
+// we want to figure out what to add and remove from the load
+// balancer
+func (l *lbListener) updateInstances(os, ns *schema.Set) error {
+	remove := expandInstanceString(os.Difference(ns).List())
+	add := expandInstanceString(ns.Difference(os).List())
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	log.Printf("[INFO] Updating Instances %s", l)
+	if len(add) > 0 {
+		_, err := l.conn.RegisterInstancesWithLoadBalancer(&computing.RegisterInstancesWithLoadBalancerInput{
+			LoadBalancerName: nifcloud.String(l.name),
+			InstancePort:     l.instancePort,
+			LoadBalancerPort: l.loadBalancerPort,
+			Instances:        add,
+		})
+		if err != nil {
+			return fmt.Errorf("Failure registering instances with LB: %s", err)
+		}
+	}
+	if len(remove) > 0 {
+		_, err := l.conn.DeregisterInstancesFromLoadBalancer(&computing.DeregisterInstancesFromLoadBalancerInput{
+			LoadBalancerName: nifcloud.String(l.name),
+			InstancePort:     l.instancePort,
+			LoadBalancerPort: l.loadBalancerPort,
+			Instances:        remove,
+		})
+		if err != nil {
+			return fmt.Errorf("Failure deregistering instances from LB: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (l *lbListener) updateFilter(o, n map[string]interface{}) error {
+	filterType := lbListenerFilterType(o, n)
+	typeChanged := lbSettingString(n, "filter_type") != "" && lbSettingString(o, "filter_type") != filterType
+
+	os := lbSettingSet(o, "filter_ip_addresses")
+	ns := lbSettingSet(n, "filter_ip_addresses")
+	remove := os.Difference(ns).List()
+	add := ns.Difference(os).List()
+
+	if typeChanged {
+		log.Printf("[INFO] Updating FilterType %s", l)
+		if err := l.setFilter(filterType, nil); err != nil {
+			return err
+		}
+	}
+
+	// DELETE old filter
+	if len(remove) > 0 {
+		log.Printf("[INFO] Updating Filter Delete %s", l)
+		if err := l.setFilter(filterType, expandDeleteFilter(remove)); err != nil {
+			return err
+		}
+	}
+
+	// ADD new/updated filter
+	if len(add) > 0 {
+		log.Printf("[INFO] Updating Filter Add %s", l)
+		if err := l.setFilter(filterType, expandAddFilter(add)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lbListenerFilterType returns the filter type to send along with the filter
+// addresses: the one of n, else the current one, else the API default "1".
+// SetFilterForLoadBalancer doesn't take an empty FilterType.
+func lbListenerFilterType(o, n map[string]interface{}) string {
+	if v := lbSettingString(n, "filter_type"); v != "" {
+		return v
+	}
+	if v := lbSettingString(o, "filter_type"); v != "" {
+		return v
+	}
+	return "1"
+}
+
+func (l *lbListener) setFilter(filterType string, addresses []*computing.RequestIPAddressesStruct) error {
+	input := &computing.SetFilterForLoadBalancerInput{
+		LoadBalancerName: nifcloud.String(l.name),
+		InstancePort:     l.instancePort,
+		LoadBalancerPort: l.loadBalancerPort,
+		FilterType:       nifcloud.String(filterType),
+	}
+	if len(addresses) > 0 {
+		input.IPAddresses = addresses
+	}
+
+	if _, err := l.conn.SetFilterForLoadBalancer(input); err != nil {
+		return fmt.Errorf("Failure setting filter for LB: %s", err)
+	}
+
+	return nil
+}
+
+func (l *lbListener) updateOption(input *computing.UpdateLoadBalancerOptionInput) error {
+	log.Printf("[INFO] Updating LoadBalancerOption %s", l)
+	input.LoadBalancerName = nifcloud.String(l.name)
+	input.InstancePort = l.instancePort
+	input.LoadBalancerPort = l.loadBalancerPort
+
+	if _, err := l.conn.UpdateLoadBalancerOption(input); err != nil {
+		return fmt.Errorf("Failure updating LoadBalancerOption for LB: %s", err)
+	}
+
+	return nil
+}
+
+func (l *lbListener) setSSLCertificate(id string) error {
+	log.Printf("[INFO] Updating SetLoadBalancerListenerSSLCertificate %s", l)
+	if id != "" {
+		_, err := l.conn.SetLoadBalancerListenerSSLCertificate(&computing.SetLoadBalancerListenerSSLCertificateInput{
+			LoadBalancerName: nifcloud.String(l.name),
+			InstancePort:     l.instancePort,
+			LoadBalancerPort: l.loadBalancerPort,
+			SSLCertificateId: nifcloud.String(id),
+		})
+		if err != nil {
+			return fmt.Errorf("Failure SetLoadBalancerListenerSSLCertificate for LB: %s", err)
+		}
+		return nil
+	}
+
+	_, err := l.conn.UnsetLoadBalancerListenerSSLCertificate(&computing.UnsetLoadBalancerListenerSSLCertificateInput{
+		LoadBalancerName: nifcloud.String(l.name),
+		InstancePort:     l.instancePort,
+		LoadBalancerPort: l.loadBalancerPort,
+	})
+	if err != nil {
+		return fmt.Errorf("Failure UnsetLoadBalancerListenerSSLCertificate for LB: %s", err)
+	}
+	return nil
+}
+
+func (l *lbListener) setSSLPolicy(id string) error {
+	log.Printf("[INFO] Updating NiftySetLoadBalancerSSLPoliciesOfListener %s", l)
+	if id != "" {
+		_, err := l.conn.NiftySetLoadBalancerSSLPoliciesOfListener(&computing.NiftySetLoadBalancerSSLPoliciesOfListenerInput{
+			LoadBalancerName: nifcloud.String(l.name),
+			InstancePort:     l.instancePort,
+			LoadBalancerPort: l.loadBalancerPort,
+			SSLPolicyId:      nifcloud.String(id),
+		})
+		if err != nil {
+			return fmt.Errorf("Failure NiftySetLoadBalancerSSLPoliciesOfListener for LB: %s", err)
+		}
+		return nil
+	}
+
+	_, err := l.conn.NiftyUnsetLoadBalancerSSLPoliciesOfListener(&computing.NiftyUnsetLoadBalancerSSLPoliciesOfListenerInput{
+		LoadBalancerName: nifcloud.String(l.name),
+		InstancePort:     l.instancePort,
+		LoadBalancerPort: l.loadBalancerPort,
+	})
+	if err != nil {
+		return fmt.Errorf("Failure NiftyUnsetLoadBalancerSSLPoliciesOfListener for LB: %s", err)
+	}
+	return nil
+}
+
+// updateNifcloudLoadBalancer calls UpdateLoadBalancer, retrying while a port
+// that was just registered is not visible yet.
+func updateNifcloudLoadBalancer(conn *computing.Computing, req *computing.UpdateLoadBalancerInput, timeout time.Duration) error {
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		_, err := conn.UpdateLoadBalancer(req)
+
+		// Retry for ...
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.LoadBalancerPort") {
+			return resource.RetryableError(err)
+		}
+
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		return nil
+	})
+
+	if isResourceTimeoutError(err) {
+		_, err = conn.UpdateLoadBalancer(req)
+	}
+
+	return err
+}
+
+// lbListenerSettings returns the settings of every listener keyed by port
+// pair, taken from the old or the new side of the diff.
+func lbListenerSettings(d *schema.ResourceData, listeners []interface{}, old bool) map[string]map[string]interface{} {
+	result := make(map[string]map[string]interface{}, len(listeners))
+
+	if len(listeners) == 1 {
+		settings := make(map[string]interface{}, len(lbListenerSettingKeys))
+		for _, k := range lbListenerSettingKeys {
+			o, n := d.GetChange(k)
+			if old {
+				settings[k] = o
+			} else {
+				settings[k] = n
+			}
+		}
+		result[lbListenerKey(listeners[0].(map[string]interface{}))] = settings
+		return result
+	}
+
+	// filter_type left out of a listener block falls back to the top-level one
+	o, n := d.GetChange("filter_type")
+	filterType := n
+	if old {
+		filterType = o
+	}
+
+	for _, lRaw := range listeners {
+		data := lRaw.(map[string]interface{})
+		settings := make(map[string]interface{}, len(lbListenerSettingKeys))
+		for _, k := range lbListenerSettingKeys {
+			settings[k] = data[k]
+		}
+		if lbSettingString(settings, "filter_type") == "" {
+			settings["filter_type"] = filterType
+		}
+		result[lbListenerKey(data)] = settings
+	}
+	return result
+}
+
+// updateNifcloudLbListeners reconciles the listener blocks of d with the load
+// balancer name. New ports are registered before old ones are deleted so that
+// the load balancer never loses its last port.
+func updateNifcloudLbListeners(d *schema.ResourceData, conn *computing.Computing, name string) error {
+	o, n := d.GetChange("listener")
+	oldListeners := o.(*schema.Set).List()
+	newListeners := n.(*schema.Set).List()
+	oldSettings := lbListenerSettings(d, oldListeners, true)
+	newSettings := lbListenerSettings(d, newListeners, false)
+
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutCreate)
+	}
+
+	oldByKey := make(map[string]map[string]interface{}, len(oldListeners))
+	for _, lRaw := range oldListeners {
+		data := lRaw.(map[string]interface{})
+		oldByKey[lbListenerKey(data)] = data
+	}
+
+	// A single listener is updated in place even when its ports change
+	if len(oldListeners) == 1 && len(newListeners) == 1 {
+		oldByKey = map[string]map[string]interface{}{
+			lbListenerKey(newListeners[0].(map[string]interface{})): oldListeners[0].(map[string]interface{}),
+		}
+	}
+
+	matched := make(map[string]bool, len(oldListeners))
+	for _, lRaw := range newListeners {
+		data := lRaw.(map[string]interface{})
+		key := lbListenerKey(data)
+
+		if oldData, ok := oldByKey[key]; ok {
+			oldKey := lbListenerKey(oldData)
+			matched[oldKey] = true
+
+			// Settings are applied against the current ports before the listener changes
+			listener := newLbListener(conn, name, oldData, timeout)
+			if err := listener.applySettings(oldSettings[oldKey], newSettings[key]); err != nil {
+				return err
+			}
+			if lbListenerBaseChanged(oldData, data) {
+				if err := listener.update(data); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		listener := newLbListener(conn, name, data, timeout)
+		if !d.IsNewResource() {
+			if err := listener.register(data); err != nil {
+				return err
+			}
+		}
+		if err := listener.applySettings(nil, newSettings[key]); err != nil {
+			return err
+		}
+	}
+
+	for key, data := range oldByKey {
+		if matched[key] || matched[lbListenerKey(data)] {
+			continue
+		}
+		if err := newLbListener(conn, name, data, timeout).delete(); err != nil {
+			return err
+		}
+	}
+
+	d.SetPartial("listener")
+	for _, k := range lbListenerSettingKeys {
+		d.SetPartial(k)
+	}
+
+	return nil
+}
+
+// deleteNifcloudLbListeners deletes every port of the listener blocks of d.
+func deleteNifcloudLbListeners(d *schema.ResourceData, conn *computing.Computing) error {
+	for _, lRaw := range d.Get("listener").(*schema.Set).List() {
+		if err := newLbListener(conn, d.Id(), lRaw.(map[string]interface{}), d.Timeout(schema.TimeoutDelete)).delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeNifcloudLbListeners returns one description per listener block of d.
+// A nil result without error means the load balancer is gone.
+func describeNifcloudLbListeners(d *schema.ResourceData, conn *computing.Computing) ([]*computing.LoadBalancerDescriptionsMemberItem, error) {
+	// Expand the "RequestLoadBalancerNamesStruct" set to nifcloud-sdk-go compat []*computing.RequestLoadBalancerNamesStruct
+	loadBalancerNames, err := expandRequestLoadBalancerNames(d.Id(), d.Get("listener").(*schema.Set).List())
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] LB describe loadBalancerNames: %v", loadBalancerNames)
+
+	// Retrieve the LB properties for updating the state
+	describeResp, err := conn.DescribeLoadBalancers(&computing.DescribeLoadBalancersInput{
+		LoadBalancerNames: loadBalancerNames,
+	})
+	if err != nil {
+		if isLoadBalancerNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Error retrieving LB: %s", err)
+	}
+
+	descriptions := describeResp.DescribeLoadBalancersResult.LoadBalancerDescriptions
+	if len(descriptions) == 0 {
+		return nil, fmt.Errorf("Unable to find LB: %s", d.Id())
+	}
+
+	return descriptions, nil
+}
+
+// setNifcloudLbListeners stores the listener blocks and their settings,
+// top-level for a single listener and nested for several.
+func setNifcloudLbListeners(d *schema.ResourceData, descriptions []*computing.LoadBalancerDescriptionsMemberItem) error {
+	if len(descriptions) == 1 && d.Get("listener").(*schema.Set).Len() <= 1 {
+		lb := descriptions[0]
+		d.Set("listener", flattenListeners(lb.ListenerDescriptions))
+		for k, v := range flattenLbListenerSettings(lb, true) {
+			if err := d.Set(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	prior := lbListenerSettings(d, d.Get("listener").(*schema.Set).List(), false)
+
+	listeners := make([]map[string]interface{}, 0, len(descriptions))
+	for _, lb := range descriptions {
+		for _, l := range flattenListeners(lb.ListenerDescriptions) {
+			key := fmt.Sprintf("%d-%d", l["lb_port"], l["instance_port"])
+
+			// health_check can't be Computed inside the block, keep it only when managed
+			settings := flattenLbListenerSettings(lb, len(lbSettingList(prior[key], "health_check")) > 0)
+			normalizeLbNestedListenerSettings(settings, prior[key])
+			for k, v := range settings {
+				l[k] = v
+			}
+			listeners = append(listeners, l)
+		}
+	}
+	if err := d.Set("listener", listeners); err != nil {
+		return err
+	}
+
+	// Settings belong to the listener blocks, clear the top-level ones
+	for _, k := range lbListenerSettingKeys {
+		d.Set(k, nil)
+	}
+
+	return nil
+}
+
+// normalizeLbNestedListenerSettings drops the API defaults the config of a
+// nested listener left out: filter_type "1" and the health check's
+// healthy_threshold and timeout.
+func normalizeLbNestedListenerSettings(settings map[string]interface{}, prior map[string]interface{}) {
+	filterType := lbSettingString(settings, "filter_type")
+	if priorType := lbSettingString(prior, "filter_type"); (filterType == "" || filterType == "1") && (priorType == "" || priorType == "1") {
+		settings["filter_type"] = priorType
+	}
+
+	checks, ok := settings["health_check"].([]map[string]interface{})
+	priorChecks := lbSettingList(prior, "health_check")
+	if !ok || len(checks) == 0 || len(priorChecks) == 0 {
+		return
+	}
+	priorCheck, _ := priorChecks[0].(map[string]interface{})
+	for _, k := range []string{"healthy_threshold", "timeout"} {
+		if lbSettingInt(priorCheck, k) == 0 {
+			delete(checks[0], k)
+		}
+	}
+}
+
+// Flattens the per-listener settings of a load balancer description
+func flattenLbListenerSettings(lb *computing.LoadBalancerDescriptionsMemberItem, withHealthCheck bool) map[string]interface{} {
+	m := map[string]interface{}{
+		"instances":                        flattenInstances(lb.Instances),
+		"filter_type":                      "",
+		"filter_ip_addresses":              []string{},
+		"session_stickiness_policy_enable": false,
+		"session_stickiness_policy_expiration_period": 0,
+		"sorry_page_enable":                           false,
+		"sorry_page_status_code":                      0,
+		"ssl_certificate_id":                          "",
+		"ssl_policy_id":                               "",
+	}
+
+	if lb.Filter != nil {
+		m["filter_type"] = nifcloud.StringValue(lb.Filter.FilterType)
+		addresses := make([]string, 0, len(lb.Filter.IPAddresses))
+		for _, a := range flattenIpAddresses(lb.Filter.IPAddresses) {
+			if a != "" {
+				addresses = append(addresses, a)
+			}
+		}
+		m["filter_ip_addresses"] = addresses
+	}
+	if lb.Option != nil && lb.Option.SessionStickinessPolicy != nil && nifcloud.BoolValue(lb.Option.SessionStickinessPolicy.Enabled) {
+		m["session_stickiness_policy_enable"] = true
+		m["session_stickiness_policy_expiration_period"] = int(nifcloud.Int64Value(lb.Option.SessionStickinessPolicy.ExpirationPeriod))
+	}
+	if lb.Option != nil && lb.Option.SorryPage != nil && nifcloud.BoolValue(lb.Option.SorryPage.Enabled) {
+		m["sorry_page_enable"] = true
+		m["sorry_page_status_code"] = int(nifcloud.Int64Value(lb.Option.SorryPage.StatusCode))
+	}
+	if v := flattenSSLCertificateID(lb.ListenerDescriptions); len(v) > 0 {
+		m["ssl_certificate_id"] = v[0]
+	}
+	if v := flattenSSLPolicyID(lb.ListenerDescriptions); len(v) > 0 {
+		m["ssl_policy_id"] = v[0]
+	}
+
+	// There's only one health check per listener
+	if withHealthCheck && lb.HealthCheck != nil && nifcloud.StringValue(lb.HealthCheck.Target) != "" {
+		m["health_check"] = flattenHealthCheck(lb.HealthCheck)
+	}
+
+	return m
+}
+
+func lbSettingSet(m map[string]interface{}, k string) *schema.Set {
+	if v, ok := m[k].(*schema.Set); ok && v != nil {
+		return v
+	}
+	return schema.NewSet(schema.HashString, nil)
+}
+
+func lbSettingList(m map[string]interface{}, k string) []interface{} {
+	if v, ok := m[k].([]interface{}); ok {
+		return v
+	}
+	return []interface{}{}
+}
+
+func lbSettingString(m map[string]interface{}, k string) string {
+	v, _ := m[k].(string)
+	return v
+}
+
+func lbSettingInt(m map[string]interface{}, k string) int {
+	v, _ := m[k].(int)
+	return v
+}
+
+func lbSettingBool(m map[string]interface{}, k string) bool {
+	v, _ := m[k].(bool)
+	return v
+}
+
+// lbListenerCustomizeDiff keeps the single and multiple listener forms apart:
+// settings are top-level with one listener and nested with several.
+func lbListenerCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	listeners := diff.Get("listener").(*schema.Set).List()
+
+	if len(listeners) > 1 {
+		// filter_type and health_check are Computed at top-level and can't be told apart from state here
+		for _, k := range []string{"instances", "filter_ip_addresses", "session_stickiness_policy_enable",
+			"session_stickiness_policy_expiration_period", "sorry_page_enable", "sorry_page_status_code",
+			"ssl_certificate_id", "ssl_policy_id"} {
+			if _, ok := diff.GetOk(k); ok {
+				return fmt.Errorf("%q must be set inside each listener block when more than one listener is configured", k)
+			}
+		}
+		return nil
+	}
+
+	for _, lRaw := range listeners {
+		data := lRaw.(map[string]interface{})
+		for _, k := range lbListenerSettingKeys {
+			v, ok := data[k]
+			if !ok {
+				continue
+			}
+			switch v := v.(type) {
+			case *schema.Set:
+				if v.Len() > 0 {
+					return fmt.Errorf("listener.%s is only used with more than one listener, set %q at top-level instead", k, k)
+				}
+			case []interface{}:
+				if len(v) > 0 {
+					return fmt.Errorf("listener.%s is only used with more than one listener, set %q at top-level instead", k, k)
+				}
+			case string:
+				if v != "" {
+					return fmt.Errorf("listener.%s is only used with more than one listener, set %q at top-level instead", k, k)
+				}
+			case int:
+				if v != 0 {
+					return fmt.Errorf("listener.%s is only used with more than one listener, set %q at top-level instead", k, k)
+				}
+			case bool:
+				if v {
+					return fmt.Errorf("listener.%s is only used with more than one listener, set %q at top-level instead", k, k)
+				}
+			}
+		}
+	}
+
+	return nil
+}
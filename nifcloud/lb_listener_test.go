@@ -0,0 +1,210 @@
+package nifcloud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestLbListenerPorts(t *testing.T) {
+	cases := []struct {
+		data         map[string]interface{}
+		lbPort       int64
+		instancePort int64
+		key          string
+	}{
+		{
+			data:         map[string]interface{}{"protocol": "HTTP", "lb_port": 0, "instance_port": 0},
+			lbPort:       80,
+			instancePort: 80,
+			key:          "80-80",
+		},
+		{
+			data:         map[string]interface{}{"protocol": "HTTPS", "lb_port": 0, "instance_port": 8080},
+			lbPort:       443,
+			instancePort: 8080,
+			key:          "443-8080",
+		},
+		{
+			data:         map[string]interface{}{"protocol": "", "lb_port": 8000, "instance_port": 8001},
+			lbPort:       8000,
+			instancePort: 8001,
+			key:          "8000-8001",
+		},
+	}
+
+	for _, tc := range cases {
+		lbPort, instancePort := lbListenerPorts(tc.data)
+		if lbPort != tc.lbPort || instancePort != tc.instancePort {
+			t.Errorf("lbListenerPorts(%v) = %d, %d, want %d, %d", tc.data, lbPort, instancePort, tc.lbPort, tc.instancePort)
+		}
+		if key := lbListenerKey(tc.data); key != tc.key {
+			t.Errorf("lbListenerKey(%v) = %q, want %q", tc.data, key, tc.key)
+		}
+	}
+}
+
+func TestNormalizeLbNestedListenerSettings_filterType(t *testing.T) {
+	cases := []struct {
+		filterType string
+		prior      string
+		expected   string
+	}{
+		// the API default is dropped when the config left it out
+		{filterType: "1", prior: "", expected: ""},
+		{filterType: "", prior: "", expected: ""},
+		{filterType: "1", prior: "1", expected: "1"},
+		{filterType: "2", prior: "", expected: "2"},
+		{filterType: "2", prior: "1", expected: "2"},
+		{filterType: "1", prior: "2", expected: "1"},
+	}
+
+	for _, tc := range cases {
+		settings := map[string]interface{}{"filter_type": tc.filterType}
+		prior := map[string]interface{}{"filter_type": tc.prior}
+		normalizeLbNestedListenerSettings(settings, prior)
+		if v := settings["filter_type"]; v != tc.expected {
+			t.Errorf("filter_type %q with prior %q: got %q, want %q", tc.filterType, tc.prior, v, tc.expected)
+		}
+	}
+}
+
+func TestNormalizeLbNestedListenerSettings_healthCheck(t *testing.T) {
+	settings := map[string]interface{}{
+		"health_check": []map[string]interface{}{{
+			"target":              "TCP:80",
+			"interval":            10,
+			"unhealthy_threshold": 2,
+			"healthy_threshold":   10,
+			"timeout":             5,
+		}},
+	}
+	prior := map[string]interface{}{
+		"health_check": []interface{}{map[string]interface{}{
+			"target":              "TCP:80",
+			"interval":            10,
+			"unhealthy_threshold": 2,
+			"healthy_threshold":   3,
+			"timeout":             0,
+		}},
+	}
+
+	normalizeLbNestedListenerSettings(settings, prior)
+
+	expected := []map[string]interface{}{{
+		"target":              "TCP:80",
+		"interval":            10,
+		"unhealthy_threshold": 2,
+		"healthy_threshold":   10,
+	}}
+	if !reflect.DeepEqual(settings["health_check"], expected) {
+		t.Errorf("health_check: got %#v, want %#v", settings["health_check"], expected)
+	}
+}
+
+func TestNormalizeLbNestedListenerSettings_noPriorHealthCheck(t *testing.T) {
+	check := map[string]interface{}{"target": "TCP:80", "healthy_threshold": 10, "timeout": 5}
+	settings := map[string]interface{}{"health_check": []map[string]interface{}{check}}
+
+	normalizeLbNestedListenerSettings(settings, map[string]interface{}{})
+
+	if len(check) != 3 {
+		t.Errorf("health_check without prior settings should be left as is, got %#v", check)
+	}
+}
+
+func TestLbListenerFilterType(t *testing.T) {
+	cases := []struct {
+		o        string
+		n        string
+		expected string
+	}{
+		{o: "", n: "2", expected: "2"},
+		{o: "1", n: "2", expected: "2"},
+		// only filter_ip_addresses changed, keep the current type
+		{o: "2", n: "", expected: "2"},
+		{o: "", n: "", expected: "1"},
+	}
+
+	for _, tc := range cases {
+		o := map[string]interface{}{"filter_type": tc.o}
+		n := map[string]interface{}{"filter_type": tc.n}
+		if v := lbListenerFilterType(o, n); v != tc.expected {
+			t.Errorf("lbListenerFilterType(%q, %q) = %q, want %q", tc.o, tc.n, v, tc.expected)
+		}
+	}
+}
+
+func TestLbListenerCustomizeDiff(t *testing.T) {
+	listener := func(lbPort int, settings map[string]interface{}) map[string]interface{} {
+		l := map[string]interface{}{
+			"protocol":      "HTTP",
+			"lb_port":       lbPort,
+			"instance_port": lbPort,
+		}
+		for k, v := range settings {
+			l[k] = v
+		}
+		return l
+	}
+
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		err    string
+	}{
+		{
+			name: "single listener with top-level settings",
+			config: map[string]interface{}{
+				"name":      "lb001",
+				"listener":  []interface{}{listener(80, nil)},
+				"instances": []interface{}{"web001"},
+			},
+		},
+		{
+			name: "single listener with nested settings",
+			config: map[string]interface{}{
+				"name":     "lb001",
+				"listener": []interface{}{listener(80, map[string]interface{}{"instances": []interface{}{"web001"}})},
+			},
+			err: "listener.instances is only used with more than one listener",
+		},
+		{
+			name: "several listeners with nested settings",
+			config: map[string]interface{}{
+				"name": "lb001",
+				"listener": []interface{}{
+					listener(80, map[string]interface{}{"instances": []interface{}{"web001"}}),
+					listener(8080, map[string]interface{}{"instances": []interface{}{"web002"}}),
+				},
+			},
+		},
+		{
+			name: "several listeners with top-level settings",
+			config: map[string]interface{}{
+				"name": "lb001",
+				"listener": []interface{}{
+					listener(80, nil),
+					listener(8080, nil),
+				},
+				"instances": []interface{}{"web001"},
+			},
+			err: `"instances" must be set inside each listener block`,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := resourceNifcloudLb().Diff(nil, terraform.NewResourceConfigRaw(tc.config), nil)
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.err, err)
+		}
+	}
+}
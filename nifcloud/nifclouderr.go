@@ -8,6 +8,124 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 )
 
+// nifcloudErrorKind is the category of a NIFCLOUD API error
+type nifcloudErrorKind int
+
+const (
+	nifcloudErrUnknown nifcloudErrorKind = iota
+	// The resource doesn't exist (any more), e.g. Client.InvalidParameterNotFound.Instance
+	nifcloudErrNotFound
+	// The resource is still used by something else, e.g. Client.ResourceAssociated.RouteTable
+	nifcloudErrInUse
+	// The resource is busy with another operation, e.g. Server.ResourceIncorrectState.IpAddress.Processing
+	nifcloudErrProcessing
+	// Too many requests, e.g. Server.ResourceIsBusy
+	nifcloudErrThrottled
+	// A temporary failure of the API itself, e.g. InternalFailure from RDB
+	nifcloudErrTransient
+)
+
+// nifcloudThrottleCodes are the error codes returned when too many requests are in flight
+var nifcloudThrottleCodes = []string{
+	"Server.ResourceIsBusy",
+	"Client.RequestLimitExceeded",
+	"Throttling",
+}
+
+// nifcloudTransientCodes are the error codes of temporary API failures worth retrying
+var nifcloudTransientCodes = []string{
+	"InternalFailure",
+	"SerializationError",
+	"Server.InternalError",
+	"Server.ServiceUnavailable",
+}
+
+// classifyNifcloudErr sorts err into one of the nifcloudErrorKind categories
+func classifyNifcloudErr(err error) nifcloudErrorKind {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return nifcloudErrUnknown
+	}
+	code := awsErr.Code()
+
+	for _, c := range nifcloudThrottleCodes {
+		if code == c {
+			return nifcloudErrThrottled
+		}
+	}
+	for _, c := range nifcloudTransientCodes {
+		if code == c {
+			return nifcloudErrTransient
+		}
+	}
+
+	switch {
+	case strings.Contains(code, "NotFound"):
+		return nifcloudErrNotFound
+	case strings.Contains(code, "InUse"),
+		strings.HasPrefix(code, "Client.ResourceAssociated."),
+		code == "DependencyViolation":
+		return nifcloudErrInUse
+	case strings.HasSuffix(code, ".Processing"),
+		strings.HasPrefix(code, "Server.ResourceIncorrectState."),
+		strings.HasPrefix(code, "Client.ResourceIncorrectState."),
+		strings.HasPrefix(code, "Server.ProcessingFailure."):
+		return nifcloudErrProcessing
+	}
+
+	return nifcloudErrUnknown
+}
+
+// isNifcloudNotFoundErr reports whether err is a not-found error with one of
+// codes. Callers pass the codes of the resource they manage only: a not-found
+// of a referenced resource (e.g. the source group of a rule) doesn't mean the
+// resource itself is gone.
+func isNifcloudNotFoundErr(err error, codes ...string) bool {
+	if classifyNifcloudErr(err) != nifcloudErrNotFound {
+		return false
+	}
+	code := err.(awserr.Error).Code()
+	for _, c := range codes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
+
+func isNifcloudInUseErr(err error) bool {
+	return classifyNifcloudErr(err) == nifcloudErrInUse
+}
+
+func isNifcloudProcessingErr(err error) bool {
+	return classifyNifcloudErr(err) == nifcloudErrProcessing
+}
+
+func isNifcloudThrottledErr(err error) bool {
+	return classifyNifcloudErr(err) == nifcloudErrThrottled
+}
+
+func isNifcloudTransientErr(err error) bool {
+	return classifyNifcloudErr(err) == nifcloudErrTransient
+}
+
+// retryOnNifcloudTransientErr runs f until it succeeds or fails with anything but a transient error
+func retryOnNifcloudTransientErr(timeout time.Duration, f func() error) error {
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		if err := f(); err != nil {
+			if isNifcloudTransientErr(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if isResourceTimeoutError(err) {
+		err = f()
+	}
+	return err
+}
+
 // Returns true if the error matches all these conditions:
 //  * err is of type awserr.Error
 //  * Error.Code() matches code
@@ -19,9 +137,11 @@ func isNifcloudErr(err error, code string, message string) bool {
 	return false
 }
 
-func retryOnNifcloudCode(code string, f func() (interface{}, error)) (interface{}, error) {
+// retryOnNifcloudCode retries f on the error code until timeout, pass the
+// d.Timeout(...) of the operation so it stays within what the user configured.
+func retryOnNifcloudCode(timeout time.Duration, code string, f func() (interface{}, error)) (interface{}, error) {
 	var resp interface{}
-	err := resource.Retry(2*time.Minute, func() *resource.RetryError {
+	err := resource.Retry(timeout, func() *resource.RetryError {
 		var err error
 		resp, err = f()
 		if err != nil {
@@ -36,11 +156,11 @@ func retryOnNifcloudCode(code string, f func() (interface{}, error)) (interface{
 	return resp, err
 }
 
-// RetryOnNifcloudCodes retries Nifcloud error codes for one minute
+// RetryOnNifcloudCodes retries Nifcloud error codes until timeout
 // Note: This function will be moved out of the nifcloud package in the future.
-func RetryOnNifcloudCodes(codes []string, f func() (interface{}, error)) (interface{}, error) {
+func RetryOnNifcloudCodes(timeout time.Duration, codes []string, f func() (interface{}, error)) (interface{}, error) {
 	var resp interface{}
-	err := resource.Retry(1*time.Minute, func() *resource.RetryError {
+	err := resource.Retry(timeout, func() *resource.RetryError {
 		var err error
 		resp, err = f()
 		if err != nil {
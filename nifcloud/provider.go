@@ -1,8 +1,11 @@
 package nifcloud
 
 import (
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/mutexkv"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
@@ -26,16 +29,95 @@ func Provider() terraform.ResourceProvider {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "The endpoint for API operations.",
+				Deprecated:  "Use endpoints to override the endpoint per service",
+			},
+			"endpoints": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						endpointsServiceComputing: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "Use this to override the default computing endpoint URL.",
+						},
+						endpointsServiceRdb: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "Use this to override the default rdb endpoint URL.",
+						},
+						endpointsServiceNas: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "Use this to override the default nas endpoint URL.",
+						},
+					},
+				},
 			},
 			"region": {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "The region where Nifcloud operations will take place.",
 			},
+			"shared_credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The path to the shared credentials file. Defaults to ~/.nifcloud/credentials.",
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The profile in the shared credentials file.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum number of times an API call is retried, throttling errors included.",
+			},
+			"retry_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The base delay in seconds between retries, doubled on every attempt. 0 uses the SDK default.",
+			},
+			"max_requests_per_second": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum number of API calls per second. 0 means unlimited.",
+			},
+			"default_description_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The text put in front of the description of every resource which has one.",
+			},
+			"skip_credentials_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip the API call checking the credentials when the provider is configured.",
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
 			// "nifcloud_instance": dataSourceInstance(),
+			"nifcloud_customer_gateway": dataSourceNifcloudCustomerGateway(),
+			"nifcloud_vpn_gateway":     dataSourceNifcloudVpnGateway(),
+			"nifcloud_eip":             dataSourceNifcloudEip(),
+			"nifcloud_instance_backups": dataSourceNifcloudInstanceBackups(),
+			"nifcloud_router":           dataSourceNifcloudRouter(),
+			"nifcloud_caller_identity":  dataSourceNifcloudCallerIdentity(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"nifcloud_instance":                                 resourceNifcloudInstance(),
@@ -43,9 +125,12 @@ func Provider() terraform.ResourceProvider {
 			"nifcloud_volume":                                   resourceNifcloudVolume(),
 			"nifcloud_securitygroup":                            resourceNifcloudSecurityGroup(),
 			"nifcloud_securitygroup_rule":                       resourceNifcloudSecurityGroupRule(),
+			"nifcloud_securitygroup_ingress_rule":               resourceNifcloudSecurityGroupIngressRule(),
 			"nifcloud_keypair":                                  resourceNifcloudKeyPair(),
 			"nifcloud_instancebackup_rule":                      resourceNifcloudInstanceBackupRule(),
 			"nifcloud_image":                                    resourceNifcloudImage(),
+			"nifcloud_image_copy":                               resourceNifcloudImageCopy(),
+			"nifcloud_image_share":                              resourceNifcloudImageShare(),
 			"nifcloud_customer_gateway":                         resourceNifcloudCustomerGateway(),
 			"nifcloud_vpn_gateway":                              resourceNifcloudVpnGateway(),
 			"nifcloud_vpn_connection":                           resourceNifcloudVpnConnection(),
@@ -60,6 +145,7 @@ func Provider() terraform.ResourceProvider {
 			"nifcloud_lb":                                       resourceNifcloudLb(),
 			"nifcloud_lb_port":                                  resourceNifcloudLbPort(),
 			"nifcloud_eip":                                      resourceNifcloudEip(),
+			"nifcloud_eip_association":                          resourceNifcloudEipAssociation(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -71,6 +157,27 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		SecretKey: d.Get("secret_key").(string),
 		Endpoint:  d.Get("endpoint").(string),
 		Region:    d.Get("region").(string),
+
+		SharedCredentialsFile: d.Get("shared_credentials_file").(string),
+		Profile:               d.Get("profile").(string),
+		MaxRetries:            d.Get("max_retries").(int),
+		RetryBackoff:          time.Duration(d.Get("retry_backoff").(int)) * time.Second,
+		MaxRequestsPerSecond:  d.Get("max_requests_per_second").(int),
+
+		DefaultDescriptionPrefix:  d.Get("default_description_prefix").(string),
+		SkipCredentialsValidation: d.Get("skip_credentials_validation").(bool),
+	}
+
+	config.Endpoints = make(map[string]string)
+	if v, ok := d.GetOk("endpoints"); ok {
+		// an empty endpoints {} block comes as a nil element
+		if endpoints, ok := v.([]interface{})[0].(map[string]interface{}); ok {
+			for k, endpoint := range endpoints {
+				if endpoint, ok := endpoint.(string); ok && endpoint != "" {
+					config.Endpoints[k] = endpoint
+				}
+			}
+		}
 	}
 
 	return config.Client()
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -24,6 +23,12 @@ func resourceNifcloudCustomerGateway() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -71,14 +76,19 @@ func resourceNifcloudCustomerGatewayCreate(d *schema.ResourceData, meta interfac
 	createOpts := &computing.CreateCustomerGatewayInput{
 		NiftyCustomerGatewayName:        nifcloud.String(name),
 		IpAddress:                       nifcloud.String(d.Get("ip_address").(string)),
-		NiftyCustomerGatewayDescription: nifcloud.String(d.Get("description").(string)),
+		NiftyCustomerGatewayDescription: withDescriptionPrefix(meta, d.Get("description").(string)),
 		NiftyLanSideCidrBlock:           nifcloud.String(d.Get("lan_side_cidr_block").(string)),
 		NiftyLanSideIpAddress:           nifcloud.String(d.Get("lan_side_ip_address").(string)),
 	}
 
 	// Create the Customer Gateway.
 	log.Printf("[DEBUG] Creating customer gateway")
-	resp, err := conn.CreateCustomerGateway(createOpts)
+	var resp *computing.CreateCustomerGatewayOutput
+	err = retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		resp, err = conn.CreateCustomerGateway(createOpts)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error creating customer gateway: %s", err)
 	}
@@ -93,7 +103,7 @@ func resourceNifcloudCustomerGatewayCreate(d *schema.ResourceData, meta interfac
 		Pending:    []string{"pending"},
 		Target:     []string{"available"},
 		Refresh:    customerGatewayRefreshFunc(conn, *customerGateway.CustomerGatewayId),
-		Timeout:    10 * time.Minute,
+		Timeout:    d.Timeout(schema.TimeoutCreate),
 		Delay:      10 * time.Second,
 		MinTimeout: 3 * time.Second,
 	}
@@ -119,7 +129,7 @@ func customerGatewayRefreshFunc(conn *computing.Computing, gatewayID string) res
 			Filter: []*computing.RequestFilterStruct{gatewayFilter},
 		})
 		if err != nil {
-			if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.CustomerGatewayId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.CustomerGatewayId") {
 				resp = nil
 			} else {
 				log.Printf("Error on CustomerGatewayRefresh: %s", err)
@@ -169,7 +179,7 @@ func resourceNifcloudCustomerGatewayRead(d *schema.ResourceData, meta interface{
 		Filter: []*computing.RequestFilterStruct{gatewayFilter},
 	})
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.CustomerGatewayId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.CustomerGatewayId") {
 			d.SetId("")
 			return nil
 		}
@@ -177,11 +187,15 @@ func resourceNifcloudCustomerGatewayRead(d *schema.ResourceData, meta interface{
 		return err
 	}
 
+	if len(resp.CustomerGatewaySet) == 0 {
+		log.Printf("[WARN] Customer Gateway (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if len(resp.CustomerGatewaySet) != 1 {
 		return fmt.Errorf("Error finding CustomerGateway: %s", d.Id())
 	}
 
-	log.Printf("[DEBUG] customer gateway describe %v", resp)
 	if *resp.CustomerGatewaySet[0].State == "deleted" {
 		log.Printf("[INFO] Customer Gateway is in `deleted` state: %s", d.Id())
 		d.SetId("")
@@ -191,7 +205,7 @@ func resourceNifcloudCustomerGatewayRead(d *schema.ResourceData, meta interface{
 	customerGateway := resp.CustomerGatewaySet[0]
 	d.Set("name", customerGateway.NiftyCustomerGatewayName)
 	d.Set("ip_address", customerGateway.IpAddress)
-	d.Set("description", customerGateway.NiftyCustomerGatewayDescription)
+	d.Set("description", trimDescriptionPrefix(meta, customerGateway.NiftyCustomerGatewayDescription))
 	d.Set("lan_side_cidr_block", customerGateway.NiftyLanSideCidrBlock)
 	d.Set("lan_side_ip_address", customerGateway.NiftyLanSideIpAddress)
 
@@ -208,7 +222,7 @@ func resourceNifcloudCustomerGatewayUpdate(d *schema.ResourceData, meta interfac
 		input := computing.NiftyModifyCustomerGatewayAttributeInput{
 			CustomerGatewayId: nifcloud.String(d.Id()),
 			Attribute:         nifcloud.String("niftyCustomerGatewayDescription"),
-			Value:             nifcloud.String(d.Get("description").(string)),
+			Value:             withDescriptionPrefix(meta, d.Get("description").(string)),
 		}
 		_, err := conn.NiftyModifyCustomerGatewayAttribute(&input)
 		if err != nil {
@@ -238,12 +252,11 @@ func resourceNifcloudCustomerGatewayDelete(d *schema.ResourceData, meta interfac
 	request := &computing.DeleteCustomerGatewayInput{
 		CustomerGatewayId: nifcloud.String(d.Id()),
 	}
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
-		resp, err := conn.DeleteCustomerGateway(request)
-		log.Printf("[DEBUG] deleting customer gateway %v", resp)
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := conn.DeleteCustomerGateway(request)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.CustomerGatewayId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.CustomerGatewayId") {
 				return nil
 			}
 			return resource.RetryableError(err)
@@ -256,7 +269,7 @@ func resourceNifcloudCustomerGatewayDelete(d *schema.ResourceData, meta interfac
 		CustomerGatewayId: nifcloud.String(d.Id()),
 	})
 	if err != nil {
-		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.CustomerGatewayId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.CustomerGatewayId") {
 			return nil
 		}
 		return fmt.Errorf("[ERROR] Error deleting CustomerGateway: %s", err)
@@ -270,12 +283,11 @@ func resourceNifcloudCustomerGatewayDelete(d *schema.ResourceData, meta interfac
 	input := &computing.DescribeCustomerGatewaysInput{
 		Filter: []*computing.RequestFilterStruct{gatewayFilter},
 	}
-	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		resp, err := conn.DescribeCustomerGateways(input)
-		log.Printf("[DEBUG] delete after describe 001 %v", resp)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.CustomerGatewayId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.CustomerGatewayId") {
 				return nil
 			}
 			return resource.NonRetryableError(err)
@@ -291,7 +303,6 @@ func resourceNifcloudCustomerGatewayDelete(d *schema.ResourceData, meta interfac
 	if isResourceTimeoutError(err) {
 		var resp *computing.DescribeCustomerGatewaysOutput
 		resp, err = conn.DescribeCustomerGateways(input)
-		log.Printf("[DEBUG] delete after describe 002 %v", resp)
 
 		if err != nil {
 			return checkCustomerGatewayDeleteResponse(resp, d.Id())
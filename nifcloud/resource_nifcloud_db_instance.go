@@ -371,8 +371,10 @@ func resourceNifcloudDbInstanceCreate(d *schema.ResourceData, meta interface{})
 			requiresModifyDbInstance = true
 		}
 
-		log.Printf("[DEBUG] DB Instance Replica create configuration: %#v", opts)
-		_, err := conn.CreateDBInstanceReadReplica(&opts)
+		err := createNifcloudDbInstance(conn, identifier, d.Timeout(schema.TimeoutCreate), func() error {
+			_, err := conn.CreateDBInstanceReadReplica(&opts)
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("Error creating DB Instance: %s", err)
 		}
@@ -474,19 +476,12 @@ func resourceNifcloudDbInstanceCreate(d *schema.ResourceData, meta interface{})
 			requiresModifyDbInstance = true
 		}
 
-		log.Printf("[DEBUG] DB Instance restore from snapshot configuration: %s", opts)
-		_, err := conn.RestoreDBInstanceFromDBSnapshot(&opts)
-
+		err := createNifcloudDbInstance(conn, identifier, d.Timeout(schema.TimeoutCreate), func() error {
+			_, err := conn.RestoreDBInstanceFromDBSnapshot(&opts)
+			return err
+		})
 		if err != nil {
-			if isNifcloudErr(err, "SerializationError", "failed decoding Query response") {
-				// nothing
-				log.Printf("[DEBUG] DB Instance restore from snapshot error: %s", err)
-			} else if isNifcloudErr(err, "InternalFailure", "System Error") {
-				// nothing
-				log.Printf("[DEBUG] DB Instance restore from snapshot error: %s", err)
-			} else {
-				return fmt.Errorf("Error creating DB Instance: %s", err)
-			}
+			return fmt.Errorf("Error creating DB Instance: %s", err)
 		}
 
 	} else {
@@ -586,21 +581,10 @@ func resourceNifcloudDbInstanceCreate(d *schema.ResourceData, meta interface{})
 			opts.Port = nifcloud.Int64(int64(attr.(int)))
 		}
 
-		log.Printf("[DEBUG] DB Instance create configuration: %#v", opts)
-		var err error
-		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
-			_, err = conn.CreateDBInstance(&opts)
-			if err != nil {
-				if isNifcloudErr(err, "Client.ResourceIncorrectState.DBSecurityGroup.Processing", "") {
-					return resource.RetryableError(err)
-				}
-				return resource.NonRetryableError(err)
-			}
-			return nil
+		err := createNifcloudDbInstance(conn, identifier, d.Timeout(schema.TimeoutCreate), func() error {
+			_, err := conn.CreateDBInstance(&opts)
+			return err
 		})
-		if isResourceTimeoutError(err) {
-			_, err = conn.CreateDBInstance(&opts)
-		}
 		if err != nil {
 //			if isNifcloudErr(err, "InvalidParameterValue", "") {
 //				opts.MasterUserPassword = nifcloud.String("********")
@@ -630,7 +614,7 @@ func resourceNifcloudDbInstanceCreate(d *schema.ResourceData, meta interface{})
 	if requiresModifyDbInstance {
 		modifyDbInstanceInput.DBInstanceIdentifier = nifcloud.String(d.Id())
 
-		log.Printf("[INFO] DB Instance (%s) configuration requires ModifyDBInstance: %s", d.Id(), modifyDbInstanceInput)
+		log.Printf("[INFO] DB Instance (%s) configuration requires ModifyDBInstance", d.Id())
 		_, err := conn.ModifyDBInstance(modifyDbInstanceInput)
 		if err != nil {
 			return fmt.Errorf("error modifying DB Instance (%s): %s", d.Id(), err)
@@ -648,7 +632,7 @@ func resourceNifcloudDbInstanceCreate(d *schema.ResourceData, meta interface{})
 			DBInstanceIdentifier: nifcloud.String(d.Id()),
 		}
 
-		log.Printf("[INFO] DB Instance (%s) configuration requires RebootDBInstance: %s", d.Id(), rebootDbInstanceInput)
+		log.Printf("[INFO] DB Instance (%s) configuration requires RebootDBInstance", d.Id())
 		_, err := conn.RebootDBInstance(rebootDbInstanceInput)
 		if err != nil {
 			return fmt.Errorf("error rebooting DB Instance (%s): %s", d.Id(), err)
@@ -745,9 +729,8 @@ func resourceNifcloudDbInstanceDelete(d *schema.ResourceData, meta interface{})
 		log.Printf("[DEBUG] Replica DB Instance destroy: %v", attr)
 		opts := rdb.DeleteDBInstanceInput{DBInstanceIdentifier: nifcloud.String(attr.(string))}
 		opts.SkipFinalSnapshot = nifcloud.Bool(true)
-		log.Printf("[DEBUG] Replica DB Instance destroy configuration: %v", opts)
 		_, err := conn.DeleteDBInstance(&opts)
-		if err != nil && !isNifcloudErr(err, "Client.InvalidParameterNotFound.DBInstance", "is already being deleted") {
+		if err != nil && !isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.DBInstance") {
 			return fmt.Errorf("error deleting Replica Database Instance %q: %s", attr, err)
 		}
 		err = waitUntilNifcloudDbInstanceIsDeleted(attr.(string), conn, d.Timeout(schema.TimeoutDelete))
@@ -771,11 +754,10 @@ func resourceNifcloudDbInstanceDelete(d *schema.ResourceData, meta interface{})
 		}
 	}
 
-	log.Printf("[DEBUG] DB Instance destroy configuration: %v", opts)
 	_, err := conn.DeleteDBInstance(&opts)
 
-	// InvalidDBInstanceState: Instance XXX is already being deleted.
-	if err != nil && !isNifcloudErr(err, "Client.InvalidParameterNotFound.DBInstance", "is already being deleted") {
+	// The instance is already gone, or being deleted
+	if err != nil && !isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.DBInstance") {
 		return fmt.Errorf("error deleting Database Instance %q: %s", d.Id(), err)
 	}
 
@@ -783,6 +765,34 @@ func resourceNifcloudDbInstanceDelete(d *schema.ResourceData, meta interface{})
 	return waitUntilNifcloudDbInstanceIsDeleted(d.Id(), conn, d.Timeout(schema.TimeoutDelete))
 }
 
+// createNifcloudDbInstance runs create, retrying transient errors and the ones
+// of a source still processing. RDB sometimes fails with InternalFailure or
+// SerializationError although the instance was created, so it is looked up
+// before trying again.
+func createNifcloudDbInstance(conn *rdb.Rdb, id string, timeout time.Duration, create func() error) error {
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		err := create()
+		if err == nil {
+			return nil
+		}
+		if isNifcloudTransientErr(err) {
+			if v, rerr := resourceNifcloudDbInstanceRetrieve(id, conn); rerr == nil && v != nil {
+				log.Printf("[WARN] DB Instance (%s) was created despite: %s", id, err)
+				return nil
+			}
+			return resource.RetryableError(err)
+		}
+		if isNifcloudProcessingErr(err) {
+			return resource.RetryableError(err)
+		}
+		return resource.NonRetryableError(err)
+	})
+	if isResourceTimeoutError(err) {
+		err = create()
+	}
+	return err
+}
+
 func waitUntilNifcloudDbInstanceIsAvailableAfterUpdate(id string, conn *rdb.Rdb, timeout time.Duration) error {
 	stateConf := &resource.StateChangeConf{
 		Pending:    resourceNifcloudDbInstanceUpdatePendingStates,
@@ -885,13 +895,11 @@ func resourceNifcloudDbInstanceUpdate(d *schema.ResourceData, meta interface{})
 
 	log.Printf("[DEBUG] Send DB Instance Modification request: %t", requestUpdate)
 	if requestUpdate {
-		log.Printf("[DEBUG] DB Instance Modification request: %s", req)
-
-		err := resource.Retry(2*time.Minute, func() *resource.RetryError {
+		err := resource.Retry(d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
 			_, err := conn.ModifyDBInstance(req)
 
-			// Retry for ...
-			if isNifcloudErr(err, "Client.ResourceIncorrectState.DBParameterGroup.Applying", "") {
+			// Retry while e.g. the parameter group is still being applied
+			if isNifcloudProcessingErr(err) {
 				return resource.RetryableError(err)
 			}
 
@@ -961,11 +969,10 @@ func resourceNifcloudDbInstanceRetrieve(id string, conn *rdb.Rdb) (*rdb.DBInstan
 		DBInstanceIdentifier: nifcloud.String(id),
 	}
 
-	log.Printf("[DEBUG] DB Instance describe configuration: %#v", opts)
 
 	resp, err := conn.DescribeDBInstances(&opts)
 	if err != nil {
-		if isNifcloudErr(err, "Client.InvalidParameterNotFound.DBInstance", "") {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.DBInstance") {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("Error retrieving DB Instances: %s", err)
@@ -25,6 +25,12 @@ func resourceNifcloudDbParameterGroup() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(3 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:          schema.TypeString,
@@ -83,11 +89,15 @@ func resourceNifcloudDbParameterGroupCreate(d *schema.ResourceData, meta interfa
 	createOpts := rdb.CreateDBParameterGroupInput{
 		DBParameterGroupName:   nifcloud.String(groupName),
 		DBParameterGroupFamily: nifcloud.String(d.Get("family").(string)),
-		Description:            nifcloud.String(d.Get("description").(string)),
+		Description:            withDescriptionPrefix(meta, d.Get("description").(string)),
 	}
 
-	log.Printf("[DEBUG] Create DB Parameter Group: %#v", createOpts)
-	resp, err := rdbconn.CreateDBParameterGroup(&createOpts)
+	var resp *rdb.CreateDBParameterGroupOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		resp, err = rdbconn.CreateDBParameterGroup(&createOpts)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error creating DB Parameter Group: %s", err)
 	}
@@ -113,7 +123,7 @@ func resourceNifcloudDbParameterGroupRead(d *schema.ResourceData, meta interface
 
 	describeResp, err := rdbconn.DescribeDBParameterGroups(&describeOpts)
 	if err != nil {
-		if isNifcloudErr(err, "Client.InvalidParameterNotFound.DBParameterGroup", "") {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.DBParameterGroup") {
 			log.Printf("[WARN] DB Parameter Group (%s) not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
@@ -121,6 +131,11 @@ func resourceNifcloudDbParameterGroupRead(d *schema.ResourceData, meta interface
 		return err
 	}
 
+	if len(describeResp.DBParameterGroups) == 0 {
+		log.Printf("[WARN] DB Parameter Group (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if len(describeResp.DBParameterGroups) != 1 ||
 		*describeResp.DBParameterGroups[0].DBParameterGroupName != d.Id() {
 		return fmt.Errorf("Unable to find Parameter Group: %#v", describeResp.DBParameterGroups)
@@ -128,7 +143,7 @@ func resourceNifcloudDbParameterGroupRead(d *schema.ResourceData, meta interface
 
 	d.Set("name", describeResp.DBParameterGroups[0].DBParameterGroupName)
 	d.Set("family", describeResp.DBParameterGroups[0].DBParameterGroupFamily)
-	d.Set("description", describeResp.DBParameterGroups[0].Description)
+	d.Set("description", trimDescriptionPrefix(meta, describeResp.DBParameterGroups[0].Description))
 
 	configParams := d.Get("parameter").(*schema.Set)
 	describeParametersOpts := rdb.DescribeDBParametersInput{
@@ -248,7 +263,6 @@ func resourceNifcloudDbParameterGroupUpdate(d *schema.ResourceData, meta interfa
 					Parameters:           paramsToModify,
 				}
 
-				log.Printf("[DEBUG] Modify DB Parameter Group: %s", modifyOpts)
 				_, err = rdbconn.ModifyDBParameterGroup(&modifyOpts)
 				if err != nil {
 					return fmt.Errorf("Error modifying DB Parameter Group: %s", err)
@@ -268,7 +282,7 @@ func resourceNifcloudDbParameterGroupDelete(d *schema.ResourceData, meta interfa
 	deleteOpts := rdb.DeleteDBParameterGroupInput{
 		DBParameterGroupName: nifcloud.String(d.Id()),
 	}
-	err := resource.Retry(3*time.Minute, func() *resource.RetryError {
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, err := conn.DeleteDBParameterGroup(&deleteOpts)
 		if err != nil {
 //			if isNifcloudErr(err, "DBParameterGroupNotFoundFault", "") || isNifcloudErr(err, "InvalidDBParameterGroupState", "") {
@@ -282,7 +296,7 @@ func resourceNifcloudDbParameterGroupDelete(d *schema.ResourceData, meta interfa
 		_, err = conn.DeleteDBParameterGroup(&deleteOpts)
 	}
 	if err != nil {
-		if isNifcloudErr(err, "Client.InvalidParameterNotFound.DBParameterGroup", "") {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.DBParameterGroup") {
 			return nil
 		}
 		return fmt.Errorf("Error deleting DB parameter group: %s", err)
@@ -25,6 +25,12 @@ func resourceNifcloudDbSecurityGroup() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"availability_zone": {
 				Type:     schema.TypeString,
@@ -75,12 +81,14 @@ func resourceNifcloudDbSecurityGroupCreate(d *schema.ResourceData, meta interfac
 
 	opts := rdb.CreateDBSecurityGroupInput{
 		DBSecurityGroupName:        nifcloud.String(d.Get("name").(string)),
-		DBSecurityGroupDescription: nifcloud.String(d.Get("description").(string)),
+		DBSecurityGroupDescription: withDescriptionPrefix(meta, d.Get("description").(string)),
 		NiftyAvailabilityZone:      nifcloud.String(d.Get("availability_zone").(string)),
 	}
 
-	log.Printf("[DEBUG] DB Security Group create configuration: %#v", opts)
-	_, err = conn.CreateDBSecurityGroup(&opts)
+	err = retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		_, err := conn.CreateDBSecurityGroup(&opts)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error creating DB Security Group: %s", err)
 	}
@@ -96,7 +104,7 @@ func resourceNifcloudDbSecurityGroupCreate(d *schema.ResourceData, meta interfac
 
 	ingresses := d.Get("ingress").(*schema.Set)
 	for _, ing := range ingresses.List() {
-		err := resourceNifcloudDbSecurityGroupAuthorizeRule(ing, *sg.DBSecurityGroupName, conn)
+		err := resourceNifcloudDbSecurityGroupAuthorizeRule(ing, *sg.DBSecurityGroupName, conn, d.Timeout(schema.TimeoutCreate))
 		if err != nil {
 			errs = append(errs, err)
 		}
@@ -113,7 +121,7 @@ func resourceNifcloudDbSecurityGroupCreate(d *schema.ResourceData, meta interfac
 		Pending: []string{"authorizing"},
 		Target:  []string{"authorized"},
 		Refresh: resourceNifcloudDbSecurityGroupStateRefreshFunc(d, meta),
-		Timeout: 10 * time.Minute,
+		Timeout: d.Timeout(schema.TimeoutCreate),
 	}
 
 	// Wait, catching any errors
@@ -128,11 +136,16 @@ func resourceNifcloudDbSecurityGroupCreate(d *schema.ResourceData, meta interfac
 func resourceNifcloudDbSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
 	sg, err := resourceNifcloudDbSecurityGroupRetrieve(d, meta)
 	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.DBSecurityGroup") {
+			log.Printf("[WARN] DB Security Group (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
 	d.Set("name", sg.DBSecurityGroupName)
-	d.Set("description", sg.DBSecurityGroupDescription)
+	d.Set("description", trimDescriptionPrefix(meta, sg.DBSecurityGroupDescription))
 
 	// Create an empty schema.Set to hold all ingress rules
 	rules := &schema.Set{
@@ -191,7 +204,7 @@ func resourceNifcloudDbSecurityGroupUpdate(d *schema.ResourceData, meta interfac
 
 		// ADD new/updated Ingress rules
 		for _, ing := range newIngress {
-			err := resourceNifcloudDbSecurityGroupAuthorizeRule(ing, *sg.DBSecurityGroupName, conn)
+			err := resourceNifcloudDbSecurityGroupAuthorizeRule(ing, *sg.DBSecurityGroupName, conn, d.Timeout(schema.TimeoutUpdate))
 			if err != nil {
 				return err
 			}
@@ -209,11 +222,10 @@ func resourceNifcloudDbSecurityGroupDelete(d *schema.ResourceData, meta interfac
 
 	opts := rdb.DeleteDBSecurityGroupInput{DBSecurityGroupName: nifcloud.String(d.Id())}
 
-	log.Printf("[DEBUG] DB Security Group destroy configuration: %v", opts)
 	_, err := conn.DeleteDBSecurityGroup(&opts)
 
 	if err != nil {
-		if isNifcloudErr(err, "Client.InvalidParameterNotFound.DBSecurityGroup", "") {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.DBSecurityGroup") {
 			return nil
 		}
 		return err
@@ -229,11 +241,13 @@ func resourceNifcloudDbSecurityGroupRetrieve(d *schema.ResourceData, meta interf
 		DBSecurityGroupName: nifcloud.String(d.Id()),
 	}
 
-	log.Printf("[DEBUG] DB Security Group describe configuration: %#v", opts)
 
 	resp, err := conn.DescribeDBSecurityGroups(&opts)
 
 	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.DBSecurityGroup") {
+			return nil, err
+		}
 		return nil, fmt.Errorf("Error retrieving DB Security Groups: %s", err)
 	}
 
@@ -246,7 +260,7 @@ func resourceNifcloudDbSecurityGroupRetrieve(d *schema.ResourceData, meta interf
 }
 
 // Authorizes the ingress rule on the db security group
-func resourceNifcloudDbSecurityGroupAuthorizeRule(ingress interface{}, dbSecurityGroupName string, conn *rdb.Rdb) error {
+func resourceNifcloudDbSecurityGroupAuthorizeRule(ingress interface{}, dbSecurityGroupName string, conn *rdb.Rdb, timeout time.Duration) error {
 	ing := ingress.(map[string]interface{})
 
 	opts := rdb.AuthorizeDBSecurityGroupIngressInput{
@@ -261,13 +275,12 @@ func resourceNifcloudDbSecurityGroupAuthorizeRule(ingress interface{}, dbSecurit
 		opts.EC2SecurityGroupName = nifcloud.String(attr.(string))
 	}
 
-	log.Printf("[DEBUG] Authorize ingress rule configuration: %#v", opts)
 
-	err := resource.Retry(2*time.Minute, func() *resource.RetryError {
+	err := resource.Retry(timeout, func() *resource.RetryError {
 		_, err := conn.AuthorizeDBSecurityGroupIngress(&opts)
 		
 		if err != nil {
-			if isNifcloudErr(err, "Client.ResourceIncorrectState.DBSecurityGroup.Unavailable", "") {
+			if isNifcloudProcessingErr(err) {
 				return resource.RetryableError(err)
 			}
 			return resource.NonRetryableError(err)
@@ -298,7 +311,6 @@ func resourceNifcloudDbSecurityGroupRevokeRule(ingress interface{}, dbSecurityGr
 		opts.EC2SecurityGroupName = nifcloud.String(attr.(string))
 	}
 
-	log.Printf("[DEBUG] Revoking ingress rule configuration: %#v", opts)
 
 	_, err := conn.RevokeDBSecurityGroupIngress(&opts)
 
@@ -19,10 +19,11 @@ func resourceNifcloudEip() *schema.Resource {
 		Update: resourceNifcloudEipUpdate,
 		Delete: resourceNifcloudEipDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceNifcloudEipImport,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
 			Read:   schema.DefaultTimeout(15 * time.Minute),
 			Update: schema.DefaultTimeout(5 * time.Minute),
 			Delete: schema.DefaultTimeout(3 * time.Minute),
@@ -90,8 +91,12 @@ func resourceNifcloudEipCreate(d *schema.ResourceData, meta interface{}) error {
 		Placement:      &computing.RequestPlacementStruct{AvailabilityZone: nifcloud.String(d.Get("availability_zone").(string))},
 	}
 
-	log.Printf("[DEBUG] EIP create configuration: %#v", allocOpts)
-	allocResp, err := conn.AllocateAddress(allocOpts)
+	var allocResp *computing.AllocateAddressOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		allocResp, err = conn.AllocateAddress(allocOpts)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error creating EIP: %s", err)
 	}
@@ -100,7 +105,6 @@ func resourceNifcloudEipCreate(d *schema.ResourceData, meta interface{}) error {
 	// the EIP api has a conditional unique ID (really), so
 	// if we're in a VPC we need to save the ID as such, otherwise
 	// it defaults to using the public IP
-	log.Printf("[DEBUG] EIP Allocate: %#v", allocResp)
 	if d.Get("nifty_private_ip").(bool) {
 		d.SetId(*allocResp.PrivateIpAddress)
 	} else {
@@ -123,8 +127,6 @@ func resourceNifcloudEipRead(d *schema.ResourceData, meta interface{}) error {
 		req.PublicIp = []*string{nifcloud.String(id)}
 	}
 
-	log.Printf(
-		"[DEBUG] EIP describe configuration: %s", req)
 
 	var err error
 	var describeAddresses *computing.DescribeAddressesOutput
@@ -133,9 +135,7 @@ func resourceNifcloudEipRead(d *schema.ResourceData, meta interface{}) error {
 		err := resource.Retry(d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
 			describeAddresses, err = conn.DescribeAddresses(req)
 			if err != nil {
-				awsErr, ok := err.(awserr.Error)
-				if ok && (awsErr.Code() == "Client.InvalidAssociationId.NotFound" ||
-					awsErr.Code() == "Client.InvalidParameterNotFound.IpAddress") {
+				if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.IpAddress", "Client.InvalidAssociationId.NotFound") {
 					return resource.RetryableError(err)
 				}
 
@@ -152,10 +152,8 @@ func resourceNifcloudEipRead(d *schema.ResourceData, meta interface{}) error {
 	} else {
 		describeAddresses, err = conn.DescribeAddresses(req)
 		if err != nil {
-			awsErr, ok := err.(awserr.Error)
-			if ok && (awsErr.Code() == "Client.InvalidAssociationId.NotFound" ||
-				awsErr.Code() == "Client.InvalidParameterNotFound.IpAddress") {
-				log.Printf("[WARN] EIP not found, removing from state: %s", req)
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.IpAddress", "Client.InvalidAssociationId.NotFound") {
+				log.Printf("[WARN] EIP %q not found, removing from state", d.Id())
 				d.SetId("")
 				return nil
 			}
@@ -181,7 +179,6 @@ func resourceNifcloudEipRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	//if address.InstanceId != nil {
-	log.Printf("[DEBUG] EIP InstanceID: %#v", address.InstanceId)
 	if v, ok := d.GetOk("instance"); ok && v != "" {
 		d.Set("instance", address.InstanceId)
 	} else {
@@ -190,17 +187,37 @@ func resourceNifcloudEipRead(d *schema.ResourceData, meta interface{}) error {
 
 	d.Set("private_ip", address.PrivateIpAddress)
 	d.Set("public_ip", address.PublicIp)
+	d.Set("description", trimDescriptionPrefix(meta, address.Description))
+	d.Set("availability_zone", address.AvailabilityZone)
 
 	return nil
 }
 
+// resourceNifcloudEipImport works out whether the imported address is a public or a private EIP
+func resourceNifcloudEipImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	conn := meta.(*NifcloudClient).computingconn
+
+	address, private, err := findAddressByIP(conn, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("nifty_private_ip", private)
+	d.Set("instance", address.InstanceId)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceNifcloudEipUpdate(d *schema.ResourceData, meta interface{}) error {
 	conn := meta.(*NifcloudClient).computingconn
 
-	if d.HasChange("description") {
+	// AllocateAddress takes no description, so a new EIP gets it here. With
+	// no description set it still has to be sent when there is a prefix.
+	description := withDescriptionPrefix(meta, d.Get("description").(string))
+	if d.HasChange("description") || (d.IsNewResource() && nifcloud.StringValue(description) != "") {
 		input := computing.NiftyModifyAddressAttributeInput{
 			Attribute: nifcloud.String("description"),
-			Value:     nifcloud.String(d.Get("description").(string)),
+			Value:     description,
 		}
 
 		if d.Get("nifty_private_ip").(bool) {
@@ -243,37 +260,11 @@ func resourceNifcloudEipUpdate(d *schema.ResourceData, meta interface{}) error {
 	if associate {
 		instanceId := v_instance.(string)
 
-		assocOpts := &computing.AssociateAddressInput{
-			NiftyReboot: nifcloud.String("true"),
-			InstanceId:  nifcloud.String(instanceId),
-		}
-
-		if d.Get("nifty_private_ip").(bool) {
-			assocOpts.PrivateIpAddress = nifcloud.String(d.Id())
-		} else {
-			assocOpts.PublicIp = nifcloud.String(d.Id())
-		}
-
-		log.Printf("[DEBUG] EIP associate configuration: %s", assocOpts)
-
-		err := resource.Retry(d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
-			_, err := conn.AssociateAddress(assocOpts)
-			if err != nil {
-				if isNifcloudErr(err, "Server.ResourceIncorrectState.IpAddress.Processing", "") {
-					return resource.RetryableError(err)
-				}
-				return resource.NonRetryableError(err)
-			}
-			return nil
-		})
-		if isResourceTimeoutError(err) {
-			_, err = conn.AssociateAddress(assocOpts)
-		}
-		if err != nil {
+		if err := associateAddress(conn, d.Id(), d.Get("nifty_private_ip").(bool), instanceId, d.Timeout(schema.TimeoutUpdate)); err != nil {
 			// Prevent saving instance if association failed
 			// e.g. missing internet gateway in VPC
 			d.Set("instance", "")
-			return fmt.Errorf("Failure associating EIP: %s", err)
+			return err
 		}
 
 		if err := waitForInstanceID(d, meta, conn, instanceId); err != nil {
@@ -338,15 +329,69 @@ func resourceNifcloudEipDelete(d *schema.ResourceData, meta interface{}) error {
 func disassociateEip(d *schema.ResourceData, meta interface{}) error {
 	conn := meta.(*NifcloudClient).computingconn
 	log.Printf("[DEBUG] Disassociating EIP: %s", d.Id())
+	return disassociateAddress(conn, d.Id(), d.Get("nifty_private_ip").(bool))
+}
+
+func waitForInstanceID(d *schema.ResourceData, meta interface{}, conn *computing.Computing, instanceID string) error {
+	return waitForAddressInstanceID(conn, d.Id(), d.Get("nifty_private_ip").(bool), instanceID, d.Timeout(schema.TimeoutRead))
+}
+
+// describeAddressesInput returns the DescribeAddresses input for a public or private EIP
+func describeAddressesInput(ip string, private bool) *computing.DescribeAddressesInput {
+	req := &computing.DescribeAddressesInput{}
+
+	if private {
+		req.PrivateIpAddress = []*string{nifcloud.String(ip)}
+	} else {
+		req.PublicIp = []*string{nifcloud.String(ip)}
+	}
+
+	return req
+}
+
+func associateAddress(conn *computing.Computing, ip string, private bool, instanceID string, timeout time.Duration) error {
+	assocOpts := &computing.AssociateAddressInput{
+		NiftyReboot: nifcloud.String("true"),
+		InstanceId:  nifcloud.String(instanceID),
+	}
+
+	if private {
+		assocOpts.PrivateIpAddress = nifcloud.String(ip)
+	} else {
+		assocOpts.PublicIp = nifcloud.String(ip)
+	}
+
+
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		_, err := conn.AssociateAddress(assocOpts)
+		if err != nil {
+			if isNifcloudProcessingErr(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if isResourceTimeoutError(err) {
+		_, err = conn.AssociateAddress(assocOpts)
+	}
+	if err != nil {
+		return fmt.Errorf("Failure associating EIP: %s", err)
+	}
+
+	return nil
+}
+
+func disassociateAddress(conn *computing.Computing, ip string, private bool) error {
 	var err error
 	disAssociateOpts := &computing.DisassociateAddressInput{
 		NiftyReboot: nifcloud.String("true"),
 	}
 
-	if d.Get("nifty_private_ip").(bool) {
-		disAssociateOpts.PrivateIpAddress = nifcloud.String(d.Id())
+	if private {
+		disAssociateOpts.PrivateIpAddress = nifcloud.String(ip)
 	} else {
-		disAssociateOpts.PublicIp = nifcloud.String(d.Id())
+		disAssociateOpts.PublicIp = nifcloud.String(ip)
 	}
 	_, err = conn.DisassociateAddress(disAssociateOpts)
 	
@@ -354,35 +399,56 @@ func disassociateEip(d *schema.ResourceData, meta interface{}) error {
 	// is the case, then it was already disassociated somehow,
 	// and that is okay. The most commmon reason for this is that
 	// the instance or ENI it was attached it was destroyed.
-	if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidAssociationID.NotFound" {
+	if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.IpAddress", "Client.InvalidAssociationID.NotFound", "Client.InvalidAssociationId.NotFound") {
 		err = nil
 	}
 	return err
 }
 
-func waitForInstanceID(d *schema.ResourceData, meta interface{}, conn *computing.Computing, instanceID string) error {
-	id := d.Id()
+// findAddress returns the EIP matching ip, or nil when it's gone
+func findAddress(conn *computing.Computing, ip string, private bool) (*computing.AddressesSetItem, error) {
+	describeAddresses, err := conn.DescribeAddresses(describeAddressesInput(ip, private))
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.IpAddress", "Client.InvalidAssociationId.NotFound") {
+			return nil, nil
+		}
+		return nil, err
+	}
 
-	req := &computing.DescribeAddressesInput{}
+	for _, addr := range describeAddresses.AddressesSet {
+		if nifcloud.StringValue(addr.PrivateIpAddress) == ip || nifcloud.StringValue(addr.PublicIp) == ip {
+			return addr, nil
+		}
+	}
 
-	if d.Get("nifty_private_ip").(bool) {
-		req.PrivateIpAddress = []*string{nifcloud.String(id)}
-	} else {
-		req.PublicIp = []*string{nifcloud.String(id)}
+	return nil, nil
+}
+
+// findAddressByIP looks ip up as a public EIP first and then as a private one
+func findAddressByIP(conn *computing.Computing, ip string) (*computing.AddressesSetItem, bool, error) {
+	for _, private := range []bool{false, true} {
+		address, err := findAddress(conn, ip, private)
+		if err != nil {
+			return nil, false, fmt.Errorf("Error retrieving EIP: %s", err)
+		}
+		if address != nil {
+			return address, private, nil
+		}
 	}
 
-	log.Printf("[DEBUG] EIP describe waitForInstanceID: %s", req)
+	return nil, false, fmt.Errorf("EIP %s not found", ip)
+}
+
+func waitForAddressInstanceID(conn *computing.Computing, id string, private bool, instanceID string, timeout time.Duration) error {
+	req := describeAddressesInput(id, private)
 
 	var err error
 	var describeAddresses *computing.DescribeAddressesOutput
 
-	err = resource.Retry(d.Timeout(schema.TimeoutRead), func() *resource.RetryError {
+	err = resource.Retry(timeout, func() *resource.RetryError {
 		describeAddresses, err = conn.DescribeAddresses(req)
 		if err != nil {
-			awsErr, ok := err.(awserr.Error)
-			if ok && (awsErr.Code() == "Client.InvalidAssociationId.NotFound" ||
-				awsErr.Code() == "Client.InvalidParameterNotFound.IpAddress") {
-				log.Printf("[DEBUG] EIP describe NonRetryableError: %s", req)
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.IpAddress", "Client.InvalidAssociationId.NotFound") {
 				return resource.NonRetryableError(err)
 			}
 			return resource.RetryableError(err)
@@ -417,6 +483,5 @@ func waitForInstanceID(d *schema.ResourceData, meta interface{}, conn *computing
 		return fmt.Errorf("Error retrieving EIP: %s", err)
 	}
 
-	log.Printf("[DEBUG] EIP describe waitForInstance after: %v", describeAddresses)
 	return err
 }
\ No newline at end of file
@@ -0,0 +1,391 @@
+package nifcloud
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+)
+
+func resourceNifcloudEipAssociation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceNifcloudEipAssociationCreate,
+		Read:   resourceNifcloudEipAssociationRead,
+		Update: resourceNifcloudEipAssociationUpdate,
+		Delete: resourceNifcloudEipAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNifcloudEipAssociationImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"public_ip": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"private_ip"},
+			},
+
+			"private_ip": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"public_ip"},
+			},
+
+			"instance": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"router_id", "load_balancer_name"},
+			},
+
+			"router_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"instance", "load_balancer_name"},
+			},
+
+			"load_balancer_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"instance", "router_id"},
+				ValidateFunc:  validateEipAssociationLoadBalancer,
+			},
+
+			"reboot_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "true",
+				ValidateFunc: validation.StringInSlice([]string{"force", "true", "false"}, false),
+			},
+		},
+	}
+}
+
+func resourceNifcloudEipAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	ip, private := eipAssociationAddress(d)
+	if ip == "" {
+		return fmt.Errorf("One of public_ip or private_ip must be configured")
+	}
+
+	instance := d.Get("instance").(string)
+	routerID := d.Get("router_id").(string)
+	if instance == "" && routerID == "" {
+		return fmt.Errorf("One of instance or router_id must be configured")
+	}
+
+	if err := associateEipTarget(conn, ip, private, instance, routerID, d.Get("reboot_mode").(string), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	d.SetId(ip)
+	log.Printf("[INFO] EIP association ID: %s", d.Id())
+
+	return resourceNifcloudEipAssociationRead(d, meta)
+}
+
+func resourceNifcloudEipAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	ip, private := eipAssociationAddress(d)
+
+	address, err := findAddress(conn, ip, private)
+	if err != nil {
+		return fmt.Errorf("Error retrieving EIP: %s", err)
+	}
+	if address == nil {
+		log.Printf("[WARN] EIP %q not found, removing association from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if private {
+		d.Set("private_ip", ip)
+	} else {
+		d.Set("public_ip", ip)
+	}
+
+	if routerID := d.Get("router_id").(string); routerID != "" {
+		ni, err := findRouterEipInterface(conn, routerID, private)
+		if err != nil {
+			return err
+		}
+		if ni != nil && nifcloud.StringValue(ni.IpAddress) == ip {
+			d.Set("router_id", routerID)
+			d.Set("instance", "")
+			return nil
+		}
+		log.Printf("[WARN] EIP %q is no longer associated with router %s", d.Id(), routerID)
+	}
+
+	// Keep track of the instance the EIP has moved to, the plan then shows
+	// the association is going to be put back
+	instance := nifcloud.StringValue(address.InstanceId)
+	if instance == "" {
+		log.Printf("[WARN] EIP %q is not associated with anything, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("instance", instance)
+	d.Set("router_id", "")
+
+	return nil
+}
+
+func resourceNifcloudEipAssociationUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	ip, private := eipAssociationAddress(d)
+
+	if d.HasChange("instance") || d.HasChange("router_id") {
+		oInstance, nInstance := d.GetChange("instance")
+		oRouterID, nRouterID := d.GetChange("router_id")
+
+		if nInstance.(string) == "" && nRouterID.(string) == "" {
+			return fmt.Errorf("One of instance or router_id must be configured")
+		}
+
+		// Release the EIP from the old target first, it can only be bound once
+		if err := disassociateEipTarget(conn, ip, private, oInstance.(string), oRouterID.(string), d.Get("reboot_mode").(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+
+		if err := associateEipTarget(conn, ip, private, nInstance.(string), nRouterID.(string), d.Get("reboot_mode").(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceNifcloudEipAssociationRead(d, meta)
+}
+
+func resourceNifcloudEipAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	ip, private := eipAssociationAddress(d)
+
+	return disassociateEipTarget(conn, ip, private, d.Get("instance").(string), d.Get("router_id").(string), d.Get("reboot_mode").(string), d.Timeout(schema.TimeoutDelete))
+}
+
+// resourceNifcloudEipAssociationImport accepts "<ip>" for an instance association
+// and "<ip>_<router_id>" for a router association.
+func resourceNifcloudEipAssociationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	conn := meta.(*NifcloudClient).computingconn
+
+	parts := strings.SplitN(d.Id(), "_", 2)
+	ip := parts[0]
+
+	address, private, err := findAddressByIP(conn, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(ip)
+	if private {
+		d.Set("private_ip", ip)
+	} else {
+		d.Set("public_ip", ip)
+	}
+
+	d.Set("reboot_mode", "true")
+	if len(parts) == 2 {
+		d.Set("router_id", parts[1])
+	} else {
+		if nifcloud.StringValue(address.InstanceId) == "" {
+			return nil, fmt.Errorf("EIP %s is not associated with an instance, use <ip>_<router_id> to import a router association", ip)
+		}
+		d.Set("instance", address.InstanceId)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// eipAssociationAddress returns the configured EIP and whether it is a private one
+func eipAssociationAddress(d *schema.ResourceData) (string, bool) {
+	if v, ok := d.GetOk("private_ip"); ok {
+		return v.(string), true
+	}
+	return d.Get("public_ip").(string), false
+}
+
+// validateEipAssociationLoadBalancer rejects load_balancer_name: the VIP of a
+// load balancer is assigned when it is created and the API has no call to put
+// an EIP on it, so the association can only be made with an instance or a router.
+func validateEipAssociationLoadBalancer(v interface{}, k string) (ws []string, errors []error) {
+	errors = append(errors, fmt.Errorf(
+		"%q: associating an EIP with a load balancer (%s) is not supported by the NIFCLOUD API, use instance or router_id", k, v.(string)))
+	return
+}
+
+func associateEipTarget(conn *computing.Computing, ip string, private bool, instance string, routerID string, rebootMode string, timeout time.Duration) error {
+	if routerID != "" {
+		log.Printf("[DEBUG] Associating EIP %s with router %s", ip, routerID)
+		return updateRouterEipAddress(conn, routerID, private, ip, rebootMode, timeout)
+	}
+
+	log.Printf("[DEBUG] Associating EIP %s with instance %s", ip, instance)
+	if err := associateAddress(conn, ip, private, instance, timeout); err != nil {
+		return err
+	}
+	return waitForAddressInstanceID(conn, ip, private, instance, timeout)
+}
+
+func disassociateEipTarget(conn *computing.Computing, ip string, private bool, instance string, routerID string, rebootMode string, timeout time.Duration) error {
+	if routerID != "" {
+		ni, err := findRouterEipInterface(conn, routerID, private)
+		if err != nil {
+			return err
+		}
+		if ni == nil || nifcloud.StringValue(ni.IpAddress) != ip {
+			// Already released from the router
+			return nil
+		}
+
+		log.Printf("[DEBUG] Disassociating EIP %s from router %s", ip, routerID)
+		return updateRouterEipAddress(conn, routerID, private, "", rebootMode, timeout)
+	}
+
+	if instance == "" {
+		return nil
+	}
+
+	address, err := findAddress(conn, ip, private)
+	if err != nil {
+		return fmt.Errorf("Error retrieving EIP: %s", err)
+	}
+	if address == nil || nifcloud.StringValue(address.InstanceId) != instance {
+		// Already released from the instance, e.g. swapped by another association
+		return nil
+	}
+
+	log.Printf("[DEBUG] Disassociating EIP %s from instance %s", ip, instance)
+	if err := disassociateAddress(conn, ip, private); err != nil {
+		return fmt.Errorf("Error disassociating EIP: %s", err)
+	}
+	return waitForAddressInstanceID(conn, ip, private, "", timeout)
+}
+
+// routerEipNetworkID returns the network of the router interface which holds an EIP
+func routerEipNetworkID(private bool) string {
+	if private {
+		return "net-COMMON_PRIVATE"
+	}
+	return "net-COMMON_GLOBAL"
+}
+
+func describeNifcloudRouter(conn *computing.Computing, routerID string) (*computing.RouterSetItem, error) {
+	routerFilter := &computing.RequestFilterStruct{
+		Name:         nifcloud.String("router-id"),
+		RequestValue: []*string{nifcloud.String(routerID)},
+	}
+
+	resp, err := conn.NiftyDescribeRouters(&computing.NiftyDescribeRoutersInput{
+		Filter: []*computing.RequestFilterStruct{routerFilter},
+	})
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouterId") {
+			return nil, fmt.Errorf("Error finding router: %s", routerID)
+		}
+		return nil, fmt.Errorf("Error finding router: %s", err)
+	}
+
+	if len(resp.RouterSet) != 1 {
+		return nil, fmt.Errorf("Error finding router: %s", routerID)
+	}
+
+	return resp.RouterSet[0], nil
+}
+
+func findRouterEipInterface(conn *computing.Computing, routerID string, private bool) (*computing.NetworkInterfaceSetItem, error) {
+	router, err := describeNifcloudRouter(conn, routerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ni := range router.NetworkInterfaceSet {
+		if nifcloud.StringValue(ni.NetworkId) == routerEipNetworkID(private) {
+			return ni, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// updateRouterEipAddress sets ip on the common network interface of the router,
+// sending back the other interfaces as they are. An empty ip releases the EIP.
+// nifcloud_router sends its configured network_interfaces as a whole when they
+// change, so the ipaddress it has for the common interface overwrites this one.
+func updateRouterEipAddress(conn *computing.Computing, routerID string, private bool, ip string, rebootMode string, timeout time.Duration) error {
+	awsMutexKV.Lock(routerID)
+	defer awsMutexKV.Unlock(routerID)
+
+	router, err := describeNifcloudRouter(conn, routerID)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	var networkInterfaces []*computing.RequestNetworkInterfaceStruct
+	for _, ni := range router.NetworkInterfaceSet {
+		networkInterface := &computing.RequestNetworkInterfaceStruct{}
+		networkInterface.SetNetworkId(nifcloud.StringValue(ni.NetworkId))
+		networkInterface.SetNetworkName(nifcloud.StringValue(ni.NetworkName))
+		networkInterface.SetDhcp(nifcloud.BoolValue(ni.Dhcp))
+		networkInterface.SetDhcpOptionsId(nifcloud.StringValue(ni.DhcpOptionsId))
+		networkInterface.SetDhcpConfigId(nifcloud.StringValue(ni.DhcpConfigId))
+		if nifcloud.StringValue(ni.NetworkId) == routerEipNetworkID(private) {
+			networkInterface.SetIpAddress(ip)
+			found = true
+		} else {
+			networkInterface.SetIpAddress(nifcloud.StringValue(ni.IpAddress))
+		}
+		networkInterfaces = append(networkInterfaces, networkInterface)
+	}
+	if !found {
+		return fmt.Errorf("router (%s) has no %s interface to associate EIP with", routerID, routerEipNetworkID(private))
+	}
+
+	input := computing.NiftyUpdateRouterNetworkInterfacesInput{
+		RouterId:         nifcloud.String(routerID),
+		NetworkInterface: networkInterfaces,
+		NiftyReboot:      nifcloud.String(rebootMode),
+		Agreement:        nifcloud.Bool(false),
+	}
+
+	_, err = conn.NiftyUpdateRouterNetworkInterfaces(&input)
+	if err != nil {
+		return fmt.Errorf("error network interfaces updating router (%s): %s", routerID, err)
+	}
+
+	// Wait for the router to be available.
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"pending", "warning"},
+		Target:     []string{"available"},
+		Refresh:    routerRefreshFunc(conn, routerID),
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+
+	_, stateErr := stateConf.WaitForState()
+	if stateErr != nil {
+		return fmt.Errorf(
+			"Error waiting for router (%s) to become ready: %s",
+			routerID, stateErr)
+	}
+
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"log"
 	"time"
@@ -24,6 +23,12 @@ func resourceNifcloudImage() *schema.Resource {
 
 		SchemaVersion: 1,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"region_name": {
 				Type:     schema.TypeString,
@@ -51,6 +56,11 @@ func resourceNifcloudImage() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"wait_for_available": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
@@ -63,11 +73,15 @@ func resourceNifcloudImageCreate(d *schema.ResourceData, meta interface{}) error
 		Name:         nifcloud.String(d.Get("name").(string)),
 		Placement:    &computing.RequestPlacementStruct{RegionName: nifcloud.String(d.Get("region_name").(string)), AvailabilityZone: nifcloud.String(d.Get("availability_zone").(string))},
 		InstanceId:   nifcloud.String(d.Get("instance_id").(string)),
-		Description:  nifcloud.String(d.Get("description").(string)),
+		Description:  withDescriptionPrefix(meta, d.Get("description").(string)),
 	}
 
-	log.Printf("[INFO] Creating Image: %v", req)
-	res, err := conn.CreateImage(&req)
+	var res *computing.CreateImageOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		res, err = conn.CreateImage(&req)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("error creating Image: %s", err)
 	}
@@ -75,12 +89,60 @@ func resourceNifcloudImageCreate(d *schema.ResourceData, meta interface{}) error
 	log.Printf("[INFO] Image ID: %s", *res.ImageId)
 	d.SetId(*res.ImageId)
 
+	if d.Get("wait_for_available").(bool) {
+		log.Printf("[DEBUG] Waiting for Image (%s) to become available", d.Id())
+
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"pending"},
+			Target:     []string{"available"},
+			Refresh:    ImageStateRefreshFunc(conn, d.Id()),
+			Timeout:    d.Timeout(schema.TimeoutCreate),
+			Delay:      30 * time.Second,
+			MinTimeout: 10 * time.Second,
+		}
+
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf(
+				"Error waiting for Image (%s) to become available: %s",
+				d.Id(), err)
+		}
+	}
+
 	return resourceNifcloudImageRead(d, meta)
 }
 
+// ImageStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch an Image
+func ImageStateRefreshFunc(conn *computing.Computing, imageID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		res, err := conn.DescribeImages(&computing.DescribeImagesInput{
+			ImageId: []*string{nifcloud.String(imageID)},
+		})
+		if err != nil {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Image") {
+				// handle consistency issues
+				return nil, "", nil
+			}
+
+			log.Printf("Error on ImageStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		if len(res.ImagesSet) != 1 {
+			return nil, "", nil
+		}
+
+		image := res.ImagesSet[0]
+		return image, nifcloud.StringValue(image.ImageState), nil
+	}
+}
+
 func resourceNifcloudImageRead(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*NifcloudClient).computingconn
+	return resourceNifcloudImageReadWithConn(d, meta, meta.(*NifcloudClient).computingconn)
+}
 
+// resourceNifcloudImageReadWithConn reads the image through conn, nifcloud_image_copy
+// passes the client of the region it copied to.
+func resourceNifcloudImageReadWithConn(d *schema.ResourceData, meta interface{}, conn *computing.Computing) error {
 	log.Printf("[INFO] Reading Image: %s", d.Id())
 	req := computing.DescribeImagesInput{
 		ImageId: []*string{nifcloud.String(d.Id())},
@@ -91,7 +153,7 @@ func resourceNifcloudImageRead(d *schema.ResourceData, meta interface{}) error {
 		var err error
 		res, err = conn.DescribeImages(&req)
 		if err != nil {
-			if err, ok := err.(awserr.Error); ok && err.Code() == "Client.InvalidParameterNotFound.Image" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Image") {
 				if d.IsNewResource() {
 					return resource.RetryableError(err)
 				}
@@ -119,7 +181,6 @@ func resourceNifcloudImageRead(d *schema.ResourceData, meta interface{}) error {
 	
 	image := res.ImagesSet[0]
 //	state := *image.ImageState
-	log.Printf("[INFO] Describe Image: %v", image)
 
 //	if state == "deregistered" {
 //		d.SetId("")
@@ -131,14 +192,16 @@ func resourceNifcloudImageRead(d *schema.ResourceData, meta interface{}) error {
 //	}
 	
 	d.Set("name", image.Name)
-	d.Set("description", image.Description)
+	d.Set("description", trimDescriptionPrefix(meta, image.Description))
 
 	return nil
 }
 
 func resourceNifcloudImageUpdate(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*NifcloudClient).computingconn
+	return resourceNifcloudImageUpdateWithConn(d, meta, meta.(*NifcloudClient).computingconn)
+}
 
+func resourceNifcloudImageUpdateWithConn(d *schema.ResourceData, meta interface{}, conn *computing.Computing) error {
 	d.Partial(true)
 
 	log.Printf("[INFO] Updating Image %s", d.Id())
@@ -146,9 +209,12 @@ func resourceNifcloudImageUpdate(d *schema.ResourceData, meta interface{}) error
 		input := computing.ModifyImageAttributeInput{
 			ImageId:   nifcloud.String(d.Id()),
 			Attribute: nifcloud.String("description"),
-			Value:     nifcloud.String(d.Get("description").(string)),
+			Value:     withDescriptionPrefix(meta, d.Get("description").(string)),
 		}
-		_, err := conn.ModifyImageAttribute(&input)
+		err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.ModifyImageAttribute(&input)
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("error description updating Image (%s): %s", d.Id(), err)
 		}	
@@ -159,7 +225,10 @@ func resourceNifcloudImageUpdate(d *schema.ResourceData, meta interface{}) error
 			Attribute: nifcloud.String("imageName"),
 			Value:     nifcloud.String(d.Get("name").(string)),
 		}
-		_, err := conn.ModifyImageAttribute(&input)
+		err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.ModifyImageAttribute(&input)
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("error name updating Image (%s): %s", d.Id(), err)
 		}	
@@ -167,15 +236,20 @@ func resourceNifcloudImageUpdate(d *schema.ResourceData, meta interface{}) error
 
 	d.Partial(false)
 
-	return resourceNifcloudImageRead(d, meta)
+	return resourceNifcloudImageReadWithConn(d, meta, conn)
 }
 
 func resourceNifcloudImageDelete(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*NifcloudClient).computingconn
+	return resourceNifcloudImageDeleteWithConn(d, meta, meta.(*NifcloudClient).computingconn)
+}
 
+func resourceNifcloudImageDeleteWithConn(d *schema.ResourceData, meta interface{}, conn *computing.Computing) error {
 	log.Printf("[INFO] Deleting Image: %s", d.Id())
-	_, err := conn.DeleteImage(&computing.DeleteImageInput{
-		ImageId: nifcloud.String(d.Id()),
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := conn.DeleteImage(&computing.DeleteImageInput{
+			ImageId: nifcloud.String(d.Id()),
+		})
+		return err
 	})
 	if err != nil {
 		return fmt.Errorf("error deleting Image (%s): %s", d.Id(), err)
@@ -0,0 +1,160 @@
+package nifcloud
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+	"log"
+	"strings"
+	"time"
+)
+
+// resourceNifcloudImageCopy distributes a private image to another region or zone.
+// The copy is an image of its own in region_name, so Read/Update/Delete are the
+// ones of nifcloud_image run against that region.
+func resourceNifcloudImageCopy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceNifcloudImageCopyCreate,
+		Read:   resourceNifcloudImageCopyRead,
+		Update: resourceNifcloudImageCopyUpdate,
+		Delete: resourceNifcloudImageCopyDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNifcloudImageCopyImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"source_image_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"region_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 40),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"wait_for_available": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceNifcloudImageCopyCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	// the copy is registered in the target region, wait for it there
+	regionConn, err := meta.(*NifcloudClient).computingConnForRegion(d.Get("region_name").(string))
+	if err != nil {
+		return err
+	}
+
+	placement := &computing.RequestPlacementStruct{RegionName: nifcloud.String(d.Get("region_name").(string))}
+	if v, ok := d.GetOk("availability_zone"); ok {
+		placement.AvailabilityZone = nifcloud.String(v.(string))
+	}
+
+	req := computing.CopyImageInput{
+		SourceImageId: nifcloud.String(d.Get("source_image_id").(string)),
+		Name:          nifcloud.String(d.Get("name").(string)),
+		Placement:     placement,
+		Description:   withDescriptionPrefix(meta, d.Get("description").(string)),
+	}
+
+	var res *computing.CopyImageOutput
+	err = retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		res, err = conn.CopyImage(&req)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error copying Image: %s", err)
+	}
+
+	log.Printf("[INFO] Image ID: %s", *res.ImageId)
+	d.SetId(*res.ImageId)
+
+	if d.Get("wait_for_available").(bool) {
+		log.Printf("[DEBUG] Waiting for Image (%s) to become available", d.Id())
+
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"pending"},
+			Target:     []string{"available"},
+			Refresh:    ImageStateRefreshFunc(regionConn, d.Id()),
+			Timeout:    d.Timeout(schema.TimeoutCreate),
+			Delay:      30 * time.Second,
+			MinTimeout: 10 * time.Second,
+		}
+
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf(
+				"Error waiting for Image (%s) to become available: %s",
+				d.Id(), err)
+		}
+	}
+
+	return resourceNifcloudImageReadWithConn(d, meta, regionConn)
+}
+
+func resourceNifcloudImageCopyRead(d *schema.ResourceData, meta interface{}) error {
+	conn, err := meta.(*NifcloudClient).computingConnForRegion(d.Get("region_name").(string))
+	if err != nil {
+		return err
+	}
+	// shared with nifcloud_image, the description is read back without the prefix
+	return resourceNifcloudImageReadWithConn(d, meta, conn)
+}
+
+func resourceNifcloudImageCopyUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn, err := meta.(*NifcloudClient).computingConnForRegion(d.Get("region_name").(string))
+	if err != nil {
+		return err
+	}
+	return resourceNifcloudImageUpdateWithConn(d, meta, conn)
+}
+
+func resourceNifcloudImageCopyDelete(d *schema.ResourceData, meta interface{}) error {
+	conn, err := meta.(*NifcloudClient).computingConnForRegion(d.Get("region_name").(string))
+	if err != nil {
+		return err
+	}
+	return resourceNifcloudImageDeleteWithConn(d, meta, conn)
+}
+
+// resourceNifcloudImageCopyImport takes <region_name>/<image_id>, or just the
+// image ID for a copy in the provider region.
+func resourceNifcloudImageCopyImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if parts := strings.SplitN(d.Id(), "/", 2); len(parts) == 2 {
+		d.Set("region_name", parts[0])
+		d.SetId(parts[1])
+	} else {
+		d.Set("region_name", meta.(*NifcloudClient).region)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,153 @@
+package nifcloud
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+	"log"
+	"time"
+)
+
+func resourceNifcloudImageShare() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceNifcloudImageShareCreate,
+		Read:   resourceNifcloudImageShareRead,
+		Update: resourceNifcloudImageShareUpdate,
+		Delete: resourceNifcloudImageShareDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"image_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"distribution_ids": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceNifcloudImageShareCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	imageID := d.Get("image_id").(string)
+	for _, id := range d.Get("distribution_ids").(*schema.Set).List() {
+		if err := associateNifcloudImage(conn, imageID, id.(string), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(imageID)
+
+	return resourceNifcloudImageShareRead(d, meta)
+}
+
+// resourceNifcloudImageShareRead only checks the image still exists,
+// the accounts it is distributed to can't be described.
+func resourceNifcloudImageShareRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	log.Printf("[INFO] Reading Image share: %s", d.Id())
+	res, err := conn.DescribeImages(&computing.DescribeImagesInput{
+		ImageId: []*string{nifcloud.String(d.Id())},
+	})
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Image") {
+			log.Printf("[DEBUG] %s no longer exists, so we'll drop the share from the state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error reading Image (%s): %s", d.Id(), err)
+	}
+
+	if len(res.ImagesSet) != 1 {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("image_id", d.Id())
+
+	return nil
+}
+
+func resourceNifcloudImageShareUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	if d.HasChange("distribution_ids") {
+		o, n := d.GetChange("distribution_ids")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		for _, id := range os.Difference(ns).List() {
+			if err := disassociateNifcloudImage(conn, d.Id(), id.(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return err
+			}
+		}
+		for _, id := range ns.Difference(os).List() {
+			if err := associateNifcloudImage(conn, d.Id(), id.(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return resourceNifcloudImageShareRead(d, meta)
+}
+
+func resourceNifcloudImageShareDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	for _, id := range d.Get("distribution_ids").(*schema.Set).List() {
+		if err := disassociateNifcloudImage(conn, d.Id(), id.(string), d.Timeout(schema.TimeoutDelete)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func associateNifcloudImage(conn *computing.Computing, imageID string, distributionID string, timeout time.Duration) error {
+	log.Printf("[INFO] Sharing Image %s with %s", imageID, distributionID)
+	err := retryOnNifcloudTransientErr(timeout, func() error {
+		_, err := conn.NiftyAssociateImage(&computing.NiftyAssociateImageInput{
+			ImageId:        nifcloud.String(imageID),
+			DistributionId: nifcloud.String(distributionID),
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error sharing Image (%s) with %s: %s", imageID, distributionID, err)
+	}
+
+	return nil
+}
+
+func disassociateNifcloudImage(conn *computing.Computing, imageID string, distributionID string, timeout time.Duration) error {
+	log.Printf("[INFO] Unsharing Image %s from %s", imageID, distributionID)
+	err := retryOnNifcloudTransientErr(timeout, func() error {
+		_, err := conn.NiftyDisassociateImage(&computing.NiftyDisassociateImageInput{
+			ImageId:        nifcloud.String(imageID),
+			DistributionId: nifcloud.String(distributionID),
+		})
+		return err
+	})
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Image") {
+			return nil
+		}
+		return fmt.Errorf("error unsharing Image (%s) from %s: %s", imageID, distributionID, err)
+	}
+
+	return nil
+}
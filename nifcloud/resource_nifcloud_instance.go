@@ -6,7 +6,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"log"
 	"time"
@@ -60,9 +59,19 @@ func resourceNifcloudInstance() *schema.Resource {
 				ValidateFunc: validation.StringLenBetween(1, 15),
 			},
 			"image_id": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"backup_instance_id"},
+			},
+			// RestoreInstancesFromBackup takes the OS settings from the backup,
+			// there is nothing to send key_name, admin, password, license or user_data with.
+			"backup_instance_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"image_id", "key_name", "admin", "password", "license", "user_data"},
 			},
 			"key_name": {
 				Type:          schema.TypeString,
@@ -122,6 +131,18 @@ func resourceNifcloudInstance() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+			"instance_state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "running",
+				ValidateFunc: validation.StringInSlice([]string{"running", "stopped"}, false),
+			},
+			"reboot_on_change": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "true",
+				ValidateFunc: validation.StringInSlice([]string{"force", "true", "false"}, false),
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -234,6 +255,15 @@ func resourceNifcloudInstanceCreate(d *schema.ResourceData, meta interface{}) er
 		}
 	}
 
+	if v, ok := d.GetOk("backup_instance_id"); ok {
+		if err := restoreNifcloudInstanceFromBackup(d, meta, v.(string), securityGroups, networkInterfaces); err != nil {
+			return err
+		}
+		return waitForNifcloudInstanceCreated(d, meta)
+	} else if _, ok := d.GetOk("image_id"); !ok {
+		return fmt.Errorf("One of image_id or backup_instance_id must be configured")
+	}
+
 	input := computing.RunInstancesInput{
 		InstanceId:            nifcloud.String(d.Get("name").(string)),
 		ImageId:               nifcloud.String(d.Get("image_id").(string)),
@@ -249,12 +279,17 @@ func resourceNifcloudInstanceCreate(d *schema.ResourceData, meta interface{}) er
 //		IpType:                nifcloud.String(d.Get("ip_type").(string)),
 //		PublicIp:              nifcloud.String(d.Get("public_ip").(string)),
 		Agreement:             nifcloud.Bool(d.Get("agreement").(bool)),
-		Description:           nifcloud.String(d.Get("description").(string)),
+		Description:           withDescriptionPrefix(meta, d.Get("description").(string)),
 		NetworkInterface:      networkInterfaces,
 		License:               licenses,
 	}
 
-	out, err := conn.RunInstances(&input)
+	var out *computing.RunInstancesOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		out, err = conn.RunInstances(&input)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error RunInstancesInput: %s", err)
 	}
@@ -266,7 +301,48 @@ func resourceNifcloudInstanceCreate(d *schema.ResourceData, meta interface{}) er
 	d.SetId(*instance.InstanceId)
 	d.Set("unique_id", instance.InstanceUniqueId)
 
-	log.Printf("[DEBUG] Waiting for instance (%s) to become running", *instance.InstanceId)
+	return waitForNifcloudInstanceCreated(d, meta)
+}
+
+// restoreNifcloudInstanceFromBackup creates the instance from a backup taken by an instance backup rule
+func restoreNifcloudInstanceFromBackup(d *schema.ResourceData, meta interface{}, backupInstanceID string, securityGroups []*string, networkInterfaces []*computing.RequestNetworkInterfaceStruct) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	name := d.Get("name").(string)
+	if name == "" {
+		return fmt.Errorf("name must be configured when restoring from backup_instance_id")
+	}
+
+	input := computing.RestoreInstancesFromBackupInput{
+		InstanceBackupInstanceId: nifcloud.String(backupInstanceID),
+		InstanceId:               nifcloud.String(name),
+		InstanceType:             nifcloud.String(d.Get("instance_type").(string)),
+		SecurityGroup:            securityGroups,
+		Placement:                &computing.RequestPlacementStruct{AvailabilityZone: nifcloud.String(d.Get("availability_zone").(string))},
+		DisableApiTermination:    nifcloud.Bool(d.Get("disable_api_termination").(bool)),
+		AccountingType:           nifcloud.String(d.Get("accounting_type").(string)),
+		Agreement:                nifcloud.Bool(d.Get("agreement").(bool)),
+		Description:              withDescriptionPrefix(meta, d.Get("description").(string)),
+		NetworkInterface:         networkInterfaces,
+	}
+
+	log.Printf("[DEBUG] Restoring instance %s from backup %s", name, backupInstanceID)
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		_, err := conn.RestoreInstancesFromBackup(&input)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Error RestoreInstancesFromBackup: %s", err)
+	}
+
+	log.Printf("[INFO] Instance Id: %s", name)
+	d.SetId(name)
+
+	return nil
+}
+
+func waitForNifcloudInstanceCreated(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] Waiting for instance (%s) to become running", d.Id())
 
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{"pending"},
@@ -280,25 +356,93 @@ func resourceNifcloudInstanceCreate(d *schema.ResourceData, meta interface{}) er
 	if _, err := stateConf.WaitForState(); err != nil {
 		return fmt.Errorf(
 			"Error waiting for instance (%s) to become ready: %s",
-			*instance.InstanceId, err)
+			d.Id(), err)
+	}
+
+	if d.Get("instance_state").(string) == "stopped" {
+		if err := stopNifcloudInstance(d, meta, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
 	}
 
 	return resourceNifcloudInstanceRead(d, meta)
 }
 
-func resourceNifcloudInstanceDelete(d *schema.ResourceData, meta interface{}) error {
+func startNifcloudInstance(d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
 	conn := meta.(*NifcloudClient).computingconn
 
-	stopInstancesInput := computing.StopInstancesInput{
+	log.Printf("[INFO] Starting instance (%s)", d.Id())
+	if _, err := conn.StartInstances(&computing.StartInstancesInput{
 		InstanceId: []*string{nifcloud.String(d.Id())},
-		Force:      nifcloud.Bool(true),
+	}); err != nil {
+		return fmt.Errorf("Error StartInstances: %s", err)
 	}
-	if _, err := conn.StopInstances(&stopInstancesInput); err != nil {
-		awsErr, ok := err.(awserr.Error)
-		if ok && awsErr.Code() == "Server.ProcessingFailure.Instance.Stop" {
-			// 何もしないで継続
-		} else {
-			return fmt.Errorf("Error StopInstances: %s", err)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"pending", "stopped"},
+		Target:     []string{"running", "warning"},
+		Refresh:    InstanceStateRefreshFunc(meta, d.Id(), []string{"terminated"}),
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error waiting for instance (%s) to become running: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func stopNifcloudInstance(d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	log.Printf("[INFO] Stopping instance (%s)", d.Id())
+	if _, err := conn.StopInstances(&computing.StopInstancesInput{
+		InstanceId: []*string{nifcloud.String(d.Id())},
+	}); err != nil {
+		return fmt.Errorf("Error StopInstances: %s", err)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"pending", "running", "warning"},
+		Target:     []string{"stopped"},
+		Refresh:    InstanceStateRefreshFunc(meta, d.Id(), []string{"terminated"}),
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf(
+			"Error waiting for instance (%s) to stopped: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceNifcloudInstanceDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+
+	// An instance stopped through instance_state doesn't need the forced stop
+	_, state, err := InstanceStateRefreshFunc(meta, d.Id(), []string{})()
+	if err != nil {
+		return fmt.Errorf("Error reading instance (%s) state: %s", d.Id(), err)
+	}
+	if state == "terminated" {
+		return nil
+	}
+
+	if state != "stopped" {
+		stopInstancesInput := computing.StopInstancesInput{
+			InstanceId: []*string{nifcloud.String(d.Id())},
+			Force:      nifcloud.Bool(true),
+		}
+		if _, err := conn.StopInstances(&stopInstancesInput); err != nil {
+			if !isNifcloudProcessingErr(err) {
+				return fmt.Errorf("Error StopInstances: %s", err)
+			}
 		}
 	}
 
@@ -322,7 +466,7 @@ func resourceNifcloudInstanceDelete(d *schema.ResourceData, meta interface{}) er
 		InstanceId: []*string{nifcloud.String(d.Id())},
 	}
 	if _, err := conn.TerminateInstances(&terminateInstancesInput); err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.Instance" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Instance") {
 			return nil
 		}
 		return fmt.Errorf("Error terminating instance: %s", err)
@@ -350,9 +494,24 @@ func resourceNifcloudInstanceDelete(d *schema.ResourceData, meta interface{}) er
 func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
 	conn := meta.(*NifcloudClient).computingconn
 
+	// A stopped instance picks the changes up on its next start, a running one
+	// has to come back to running after the reboot asked for by reboot_on_change.
+	updatePending := []string{"pending"}
+	updateTargets := []string{"running", "warning"}
+	_, state, err := InstanceStateRefreshFunc(meta, d.Id(), []string{})()
+	if err != nil {
+		return err
+	}
+	if state == "stopped" {
+		updateTargets = append(updateTargets, "stopped")
+	} else {
+		updatePending = append(updatePending, "stopped")
+	}
+	niftyReboot := nifcloud.String(d.Get("reboot_on_change").(string))
+
 	updateStateConf := &resource.StateChangeConf{
-		Pending:    []string{"pending"},
-		Target:     []string{"running", "stopped", "warning"},
+		Pending:    updatePending,
+		Target:     updateTargets,
 		Refresh:    InstanceStateRefreshFunc(meta, d.Id(), []string{"waiting", "terminated"}),
 		Timeout:    d.Timeout(schema.TimeoutUpdate),
 		Delay:      10 * time.Second,
@@ -363,9 +522,10 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 
 	if d.HasChange("description") {
 		_, err := conn.ModifyInstanceAttribute(&computing.ModifyInstanceAttributeInput{
-			InstanceId: nifcloud.String(d.Id()),
-			Attribute:  nifcloud.String("description"),
-			Value:      nifcloud.String(d.Get("description").(string)),
+			InstanceId:  nifcloud.String(d.Id()),
+			Attribute:   nifcloud.String("description"),
+			Value:       withDescriptionPrefix(meta, d.Get("description").(string)),
+			NiftyReboot: niftyReboot,
 		})
 		if err != nil {
 			return fmt.Errorf("Error ModifyInstanceAttribute: %s", err)
@@ -383,7 +543,7 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 			InstanceId:  nifcloud.String(d.Id()),
 			Attribute:   nifcloud.String("instanceType"),
 			Value:       nifcloud.String(d.Get("instance_type").(string)),
-//			NiftyReboot: nifcloud.Bool(true),
+			NiftyReboot: niftyReboot,
 		})
 		if err != nil {
 			return fmt.Errorf("Error ModifyInstanceAttribute: %s", err)
@@ -398,9 +558,10 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 
 	if d.HasChange("disable_api_termination") {
 		_, err := conn.ModifyInstanceAttribute(&computing.ModifyInstanceAttributeInput{
-			InstanceId: nifcloud.String(d.Id()),
-			Attribute:  nifcloud.String("disableApiTermination"),
-			Value:      nifcloud.String(strconv.FormatBool(d.Get("disable_api_termination").(bool))),
+			InstanceId:  nifcloud.String(d.Id()),
+			Attribute:   nifcloud.String("disableApiTermination"),
+			Value:       nifcloud.String(strconv.FormatBool(d.Get("disable_api_termination").(bool))),
+			NiftyReboot: niftyReboot,
 		})
 		if err != nil {
 			return fmt.Errorf("Error ModifyInstanceAttribute: %s", err)
@@ -418,6 +579,7 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 			InstanceId:  nifcloud.String(d.Id()),
 			Attribute:   nifcloud.String("instanceName"),
 			Value:       nifcloud.String(d.Get("name").(string)),
+			NiftyReboot: niftyReboot,
 		})
 
 		if err != nil {
@@ -427,8 +589,8 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 		d.SetId(d.Get("name").(string))
 
 		updateStateConf := &resource.StateChangeConf{
-			Pending:    []string{"pending", "terminated"},
-			Target:     []string{"running", "stopped", "warning"},
+			Pending:    append([]string{"terminated"}, updatePending...),
+			Target:     updateTargets,
 			Refresh:    InstanceStateRefreshFunc(meta, d.Id(), []string{"waiting"}),
 			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
@@ -444,9 +606,10 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 
 	if d.HasChange("accounting_type") {
 		_, err := conn.ModifyInstanceAttribute(&computing.ModifyInstanceAttributeInput{
-			InstanceId: nifcloud.String(d.Id()),
-			Attribute:  nifcloud.String("accountingType"),
-			Value:      nifcloud.String(d.Get("accounting_type").(string)),
+			InstanceId:  nifcloud.String(d.Id()),
+			Attribute:   nifcloud.String("accountingType"),
+			Value:       nifcloud.String(d.Get("accounting_type").(string)),
+			NiftyReboot: niftyReboot,
 		})
 		if err != nil {
 			return fmt.Errorf("Error ModifyInstanceAttribute: %s", err)
@@ -461,9 +624,10 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 
 	if d.HasChange("security_groups") {
 		_, err := conn.ModifyInstanceAttribute(&computing.ModifyInstanceAttributeInput{
-			InstanceId: nifcloud.String(d.Id()),
-			Attribute:  nifcloud.String("groupId"),
-			Value:      nifcloud.String(d.Get("security_groups").([]interface{})[0].(string)),
+			InstanceId:  nifcloud.String(d.Id()),
+			Attribute:   nifcloud.String("groupId"),
+			Value:       nifcloud.String(d.Get("security_groups").([]interface{})[0].(string)),
+			NiftyReboot: niftyReboot,
 		})
 		if err != nil {
 			return fmt.Errorf("Error ModifyInstanceAttribute: %s", err)
@@ -510,7 +674,7 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 		_, err := conn.NiftyUpdateInstanceNetworkInterfaces(&computing.NiftyUpdateInstanceNetworkInterfacesInput{
 			InstanceId:       nifcloud.String(d.Id()),
 			NetworkInterface: networkInterfaces,
-			//NiftyReboot:      nifcloud.String("true"),
+			NiftyReboot:      niftyReboot,
 		})
 		if err != nil {
 			return fmt.Errorf("Error NiftyUpdateInstanceNetworkInterfaces: %s", err)
@@ -523,6 +687,19 @@ func resourceNifcloudInstanceUpdate(d *schema.ResourceData, meta interface{}) er
 		}
 	}
 
+	if d.HasChange("instance_state") {
+		switch d.Get("instance_state").(string) {
+		case "running":
+			if err := startNifcloudInstance(d, meta, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return err
+			}
+		case "stopped":
+			if err := stopNifcloudInstance(d, meta, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return err
+			}
+		}
+	}
+
 	d.Partial(false)
 
 	return resourceNifcloudInstanceRead(d, meta)
@@ -535,8 +712,7 @@ func resourceNifcloudInstanceRead(d *schema.ResourceData, meta interface{}) erro
 
 	out, err := conn.DescribeInstances(&input)
 	if err != nil {
-		awsErr, ok := err.(awserr.Error)
-		if ok && awsErr.Code() == "Client.InvalidParameterNotFound.Instance" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Instance") {
 			d.SetId("")
 			return nil
 		}
@@ -552,7 +728,9 @@ func resourceNifcloudInstanceRead(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	if reservation == nil {
-		return fmt.Errorf("Couldn't find Instance resource: %s", err)
+		log.Printf("[WARN] Instance (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
 	}
 
 	return setInstanceResourceData(d, meta, reservation)
@@ -570,7 +748,7 @@ func InstanceStateRefreshFunc(meta interface{}, instanceID string, failStates []
 		out, err := conn.DescribeInstances(&input)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.Instance" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Instance") {
 				return "", "terminated", nil
 			}
 
@@ -605,8 +783,7 @@ func setInstanceResourceData(d *schema.ResourceData, meta interface{}, reservati
 	})
 
 	if err != nil {
-		awsErr, ok := err.(awserr.Error)
-		if ok && awsErr.Code() == "Client.InvalidParameterNotFound.Instance" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Instance") {
 			d.SetId("")
 			return nil
 		}
@@ -619,27 +796,31 @@ func setInstanceResourceData(d *schema.ResourceData, meta interface{}, reservati
 	})
 
 	if err != nil {
-		awsErr, ok := err.(awserr.Error)
-		if ok && awsErr.Code() == "Client.InvalidParameterNotFound.Instance" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Instance") {
 			d.SetId("")
 			return nil
 		}
 		return fmt.Errorf("Error retrieving Instance: %s", err)
 	}
 
-	log.Printf("[INFO] **********************************\n instance : %v\n ***************************", instance)
-	log.Printf("[INFO] **********************************\n NetworkInterfaceSet : %v\n ***************************", instance.NetworkInterfaceSet)
 	d.Set("image_id", instance.ImageId)
 	d.Set("instance_type", instance.InstanceType)
 	//d.Set("accounting_type", instance.AccountingType)
 	d.Set("accounting_type", instance.NextMonthAccountingType)
-	d.Set("description", instance.Description)
+	d.Set("description", trimDescriptionPrefix(meta, instance.Description))
 	d.Set("availability_zone", instance.Placement.AvailabilityZone)
 	d.Set("user_data", outUserData.UserData)
 //	d.Set("ip_type", instance.IpType)
 	d.Set("ip_address", instance.IpAddress)
 	d.Set("private_ip_address", instance.PrivateIpAddress)
 
+	switch *instance.InstanceState.Name {
+	case "stopped":
+		d.Set("instance_state", "stopped")
+	case "running", "warning":
+		d.Set("instance_state", "running")
+	}
+
 	d.Set("disable_api_termination", outDisableAPITermination.DisableApiTermination.Value)
 
 	// only windows
@@ -2,14 +2,18 @@ package nifcloud
 
 import (
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"log"
+	"time"
 )
 
+// instanceBackupRuleTimeSlotIDs are the backup time slots, two hours each from 00:00 ("1") to 22:00 ("12")
+var instanceBackupRuleTimeSlotIDs = []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+
 func resourceNifcloudInstanceBackupRule() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNifcloudInstanceBackupRuleCreate,
@@ -20,7 +24,14 @@ func resourceNifcloudInstanceBackupRule() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
-		SchemaVersion: 1,
+		SchemaVersion: 2,
+		MigrateState:  resourceNifcloudInstanceBackupRuleMigrateState,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"backup_instance_max_count": {
@@ -34,21 +45,27 @@ func resourceNifcloudInstanceBackupRule() *schema.Resource {
 				ValidateFunc: validation.StringLenBetween(1, 15),
 			},
 			"time_slot_id": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "1",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1",
+				ValidateFunc: validation.StringInSlice(instanceBackupRuleTimeSlotIDs, false),
 			},
 			"instance_unique_id": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Required: true,
 				MinItems: 1,
-				MaxItems: 1,
 				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
 			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"wait_for_available": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
@@ -56,23 +73,22 @@ func resourceNifcloudInstanceBackupRule() *schema.Resource {
 func resourceNifcloudInstanceBackupRuleCreate(d *schema.ResourceData, meta interface{}) error {
 	conn := meta.(*NifcloudClient).computingconn
 
-	var instanceUniqueIDS []*string
-	if ius := d.Get("instance_unique_id").([]interface{}); ius != nil {
-		for _, v := range ius {
-			instanceUniqueIDS = append(instanceUniqueIDS, nifcloud.String(v.(string)))
-		}
-	}
+	instanceUniqueIDS := expandStringList(d.Get("instance_unique_id").(*schema.Set).List())
 
 	input := computing.CreateInstanceBackupRuleInput{
 		BackupInstanceMaxCount: nifcloud.Int64(int64(d.Get("backup_instance_max_count").(int))),
 		InstanceBackupRuleName: nifcloud.String(d.Get("name").(string)),
 		TimeSlotId:             nifcloud.String(d.Get("time_slot_id").(string)),
 		InstanceUniqueId:       instanceUniqueIDS,
-		Description:            nifcloud.String(d.Get("description").(string)),
+		Description:            withDescriptionPrefix(meta, d.Get("description").(string)),
 	}
 
-	log.Printf("[INFO] Creating InstanceBackupRule: %v", input)
-	out, err := conn.CreateInstanceBackupRule(&input)
+	var out *computing.CreateInstanceBackupRuleOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		out, err = conn.CreateInstanceBackupRule(&input)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("error creating InstanceBackupRule: %s", err)
 	}
@@ -81,9 +97,53 @@ func resourceNifcloudInstanceBackupRuleCreate(d *schema.ResourceData, meta inter
 	log.Printf("[INFO] InstanceBackupRule ID: %s", *rules.InstanceBackupRuleId)
 	d.SetId(*rules.InstanceBackupRuleId)
 
+	if d.Get("wait_for_available").(bool) {
+		log.Printf("[DEBUG] Waiting for InstanceBackupRule (%s) to become available", d.Id())
+
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"processing", "pending"},
+			Target:     []string{"available"},
+			Refresh:    InstanceBackupRuleStateRefreshFunc(conn, d.Id()),
+			Timeout:    d.Timeout(schema.TimeoutCreate),
+			Delay:      30 * time.Second,
+			MinTimeout: 10 * time.Second,
+		}
+
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf(
+				"Error waiting for InstanceBackupRule (%s) to become available: %s",
+				d.Id(), err)
+		}
+	}
+
 	return resourceNifcloudInstanceBackupRuleRead(d, meta)
 }
 
+// InstanceBackupRuleStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch an InstanceBackupRule
+func InstanceBackupRuleStateRefreshFunc(conn *computing.Computing, ruleID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		out, err := conn.DescribeInstanceBackupRules(&computing.DescribeInstanceBackupRulesInput{
+			InstanceBackupRuleId: []*string{nifcloud.String(ruleID)},
+		})
+		if err != nil {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.InstanceBackupRuleId") {
+				// handle consistency issues
+				return nil, "", nil
+			}
+
+			log.Printf("Error on InstanceBackupRuleStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		if len(out.InstanceBackupRulesSet) == 0 {
+			return nil, "", nil
+		}
+
+		rule := out.InstanceBackupRulesSet[0]
+		return rule, nifcloud.StringValue(rule.InstanceBackupRuleStatus), nil
+	}
+}
+
 func resourceNifcloudInstanceBackupRuleRead(d *schema.ResourceData, meta interface{}) error {
 	conn := meta.(*NifcloudClient).computingconn
 
@@ -93,7 +153,7 @@ func resourceNifcloudInstanceBackupRuleRead(d *schema.ResourceData, meta interfa
 	}
 
 	out, err := conn.DescribeInstanceBackupRules(&input)
-	if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.InstanceBackupRuleId" {
+	if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.InstanceBackupRuleId") {
 		log.Printf("[WARN] InstanceBackupRule (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -103,14 +163,25 @@ func resourceNifcloudInstanceBackupRuleRead(d *schema.ResourceData, meta interfa
 		return fmt.Errorf("error reading InstanceBackupRule (%s): %s", d.Id(), err)
 	}
 
+	if len(out.InstanceBackupRulesSet) == 0 {
+		log.Printf("[WARN] InstanceBackupRule (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
 	rules := out.InstanceBackupRulesSet[0]
-	log.Printf("[INFO] Describe InstanceBackupRule: %v", rules)
 
 	d.Set("name", rules.InstanceBackupRuleName)
 	d.Set("backup_instance_max_count", rules.BackupInstanceMaxCount)
 	d.Set("time_slot_id", rules.TimeSlotId)
-	d.Set("instance_unique_id", rules.InstancesSet[0].InstanceUniqueId)
-	d.Set("description", rules.Description)
+	instanceUniqueIDS := make([]string, 0, len(rules.InstancesSet))
+	for _, instance := range rules.InstancesSet {
+		instanceUniqueIDS = append(instanceUniqueIDS, nifcloud.StringValue(instance.InstanceUniqueId))
+	}
+	if err := d.Set("instance_unique_id", instanceUniqueIDS); err != nil {
+		return fmt.Errorf("error setting instance_unique_id: %s", err)
+	}
+	d.Set("description", trimDescriptionPrefix(meta, rules.Description))
 
 	return nil
 }
@@ -122,9 +193,12 @@ func resourceNifcloudInstanceBackupRuleUpdate(d *schema.ResourceData, meta inter
 	if d.HasChange("description") {
 		input := computing.ModifyInstanceBackupRuleAttributeInput{
 			InstanceBackupRuleId: nifcloud.String(d.Id()),
-			Description:          nifcloud.String(d.Get("description").(string)),
+			Description:          withDescriptionPrefix(meta, d.Get("description").(string)),
 		}
-		_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+		err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("error description updating InstanceBackupRule (%s): %s", d.Id(), err)
 		}	
@@ -134,7 +208,10 @@ func resourceNifcloudInstanceBackupRuleUpdate(d *schema.ResourceData, meta inter
 			InstanceBackupRuleId: nifcloud.String(d.Id()),
 			TimeSlotId:           nifcloud.String(d.Get("time_slot_id").(string)),
 		}
-		_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+		err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("error time_slot_id updating InstanceBackupRule (%s): %s", d.Id(), err)
 		}	
@@ -144,17 +221,36 @@ func resourceNifcloudInstanceBackupRuleUpdate(d *schema.ResourceData, meta inter
 			InstanceBackupRuleId:   nifcloud.String(d.Id()),
 			InstanceBackupRuleName: nifcloud.String(d.Get("name").(string)),
 		}
-		_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+		err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("error name updating InstanceBackupRule (%s): %s", d.Id(), err)
 		}	
 	}
+	if d.HasChange("instance_unique_id") {
+		input := computing.ModifyInstanceBackupRuleAttributeInput{
+			InstanceBackupRuleId: nifcloud.String(d.Id()),
+			InstanceUniqueId:     expandStringList(d.Get("instance_unique_id").(*schema.Set).List()),
+		}
+		err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("error instance_unique_id updating InstanceBackupRule (%s): %s", d.Id(), err)
+		}
+	}
 	if d.HasChange("backup_instance_max_count") {
 		input := computing.ModifyInstanceBackupRuleAttributeInput{
 			InstanceBackupRuleId:   nifcloud.String(d.Id()),
 			BackupInstanceMaxCount: nifcloud.Int64(int64(d.Get("backup_instance_max_count").(int))),
 		}
-		_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+		err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.ModifyInstanceBackupRuleAttribute(&input)
+			return err
+		})
 		if err != nil {
 			return fmt.Errorf("error backup_instance_max_count updating InstanceBackupRule (%s): %s", d.Id(), err)
 		}	
@@ -167,8 +263,11 @@ func resourceNifcloudInstanceBackupRuleDelete(d *schema.ResourceData, meta inter
 	conn := meta.(*NifcloudClient).computingconn
 
 	log.Printf("[INFO] Deleting InstanceBackupRule: %s", d.Id())
-	_, err := conn.DeleteInstanceBackupRule(&computing.DeleteInstanceBackupRuleInput{
-		InstanceBackupRuleId: nifcloud.String(d.Id()),
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := conn.DeleteInstanceBackupRule(&computing.DeleteInstanceBackupRuleInput{
+			InstanceBackupRuleId: nifcloud.String(d.Id()),
+		})
+		return err
 	})
 	if err != nil {
 		return fmt.Errorf("error deleting InstanceBackupRule (%s): %s", d.Id(), err)
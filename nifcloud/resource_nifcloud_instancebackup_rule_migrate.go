@@ -0,0 +1,46 @@
+package nifcloud
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func resourceNifcloudInstanceBackupRuleMigrateState(
+	v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0, 1:
+		log.Printf("[INFO] Found NIFCLOUD Instance Backup Rule State v%d; migrating to v2", v)
+		return migrateInstanceBackupRuleStateV1toV2(is)
+	default:
+		return is, fmt.Errorf("Unexpected schema version: %d", v)
+	}
+}
+
+func migrateInstanceBackupRuleStateV1toV2(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is.Empty() {
+		log.Println("[DEBUG] Empty InstanceState; nothing to migrate.")
+		return is, nil
+	}
+
+	// instance_unique_id changed from TypeList to TypeSet,
+	// so instance_unique_id.0 becomes instance_unique_id.<hash>
+	var ids []string
+	for k, v := range is.Attributes {
+		parts := strings.SplitN(k, ".", 2)
+		if len(parts) != 2 || parts[0] != "instance_unique_id" || parts[1] == "#" {
+			continue
+		}
+		delete(is.Attributes, k)
+		ids = append(ids, v)
+	}
+	for _, v := range ids {
+		is.Attributes[fmt.Sprintf("instance_unique_id.%d", schema.HashString(v))] = v
+	}
+
+	log.Printf("[DEBUG] Migrated %d instance_unique_id entries to a set", len(ids))
+	return is, nil
+}
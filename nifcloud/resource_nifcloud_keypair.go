@@ -3,13 +3,13 @@ package nifcloud
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 )
 
@@ -23,6 +23,12 @@ func resourceNifcloudKeyPair() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
 		SchemaVersion: 1,
 		MigrateState:  resourceAwsKeyPairMigrateState,
 
@@ -74,9 +80,14 @@ func resourceNifcloudKeyPairCreate(d *schema.ResourceData, meta interface{}) err
 	req := &computing.ImportKeyPairInput{
 		KeyName:           nifcloud.String(keyName),
 		PublicKeyMaterial: nifcloud.String(base64Encode([]byte(publicKey))),
-		Description:       nifcloud.String(d.Get("description").(string)),
+		Description:       withDescriptionPrefix(meta, d.Get("description").(string)),
 	}
-	resp, err := conn.ImportKeyPair(req)
+	var resp *computing.ImportKeyPairOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		resp, err = conn.ImportKeyPair(req)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error import KeyPair: %s", err)
 	}
@@ -89,10 +100,13 @@ func resourceNifcloudKeyPairUpdate(d *schema.ResourceData, meta interface{}) err
 	conn := meta.(*NifcloudClient).computingconn
 
 	if d.HasChange("description") {
-		_, err := conn.NiftyModifyKeyPairAttribute(&computing.NiftyModifyKeyPairAttributeInput{
-			KeyName:   nifcloud.String(d.Id()),
-			Attribute: nifcloud.String("description"),
-			Value:     nifcloud.String(d.Get("description").(string)),
+		err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.NiftyModifyKeyPairAttribute(&computing.NiftyModifyKeyPairAttributeInput{
+				KeyName:   nifcloud.String(d.Id()),
+				Attribute: nifcloud.String("description"),
+				Value:     withDescriptionPrefix(meta, d.Get("description").(string)),
+			})
+			return err
 		})
 		if err != nil {
 			return err
@@ -109,8 +123,7 @@ func resourceNifcloudKeyPairRead(d *schema.ResourceData, meta interface{}) error
 	}
 	resp, err := conn.DescribeKeyPairs(req)
 	if err != nil {
-		awsErr, ok := err.(awserr.Error)
-		if ok && awsErr.Code() == "Client.InvalidParameterNotFound.KeyPair" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.KeyPair") {
 			d.SetId("")
 			return nil
 		}
@@ -121,7 +134,7 @@ func resourceNifcloudKeyPairRead(d *schema.ResourceData, meta interface{}) error
 		if *keyPair.KeyName == d.Id() {
 			d.Set("key_name", keyPair.KeyName)
 			d.Set("fingerprint", keyPair.KeyFingerprint)
-			d.Set("description", keyPair.Description)
+			d.Set("description", trimDescriptionPrefix(meta, keyPair.Description))
 			return nil
 		}
 	}
@@ -132,8 +145,10 @@ func resourceNifcloudKeyPairRead(d *schema.ResourceData, meta interface{}) error
 func resourceNifcloudKeyPairDelete(d *schema.ResourceData, meta interface{}) error {
 	conn := meta.(*NifcloudClient).computingconn
 
-	_, err := conn.DeleteKeyPair(&computing.DeleteKeyPairInput{
-		KeyName: nifcloud.String(d.Id()),
+	return retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := conn.DeleteKeyPair(&computing.DeleteKeyPairInput{
+			KeyName: nifcloud.String(d.Id()),
+		})
+		return err
 	})
-	return err
 }
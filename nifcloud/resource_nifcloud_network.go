@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -25,6 +24,7 @@ func resourceNifcloudNetwork() *schema.Resource {
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
@@ -68,11 +68,16 @@ func resourceNifcloudNetworkCreate(d *schema.ResourceData, meta interface{}) err
 		CidrBlock:        nifcloud.String(d.Get("cidr_block").(string)),
 		AvailabilityZone: nifcloud.String(d.Get("availability_zone").(string)),
 		AccountingType:   nifcloud.String(d.Get("accounting_type").(string)),
-		Description:      nifcloud.String(d.Get("description").(string)),
+		Description:      withDescriptionPrefix(meta, d.Get("description").(string)),
 	}
 
 	var err error
-	resp, err := conn.NiftyCreatePrivateLan(createOpts)
+	var resp *computing.NiftyCreatePrivateLanOutput
+	err = retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		resp, err = conn.NiftyCreatePrivateLan(createOpts)
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("Error creating subnet: %s", err)
@@ -111,14 +116,16 @@ func resourceNifcloudNetworkRead(d *schema.ResourceData, meta interface{}) error
 	})
 
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.NetworkId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.NetworkId") {
 			// Update state to indicate the subnet no longer exists.
 			d.SetId("")
 			return nil
 		}
 		return err
 	}
-	if resp == nil {
+	if resp == nil || len(resp.PrivateLanSet) == 0 {
+		log.Printf("[WARN] Network (%s) not found, removing from state", d.Id())
+		d.SetId("")
 		return nil
 	}
 
@@ -129,7 +136,7 @@ func resourceNifcloudNetworkRead(d *schema.ResourceData, meta interface{}) error
 	d.Set("availability_zone", subnet.AvailabilityZone)
 	//d.Set("accounting_type", subnet.AccountingType)
 	d.Set("accounting_type", subnet.NextMonthAccountingType)
-	d.Set("description", subnet.Description)
+	d.Set("description", trimDescriptionPrefix(meta, subnet.Description))
 
 	return nil
 }
@@ -152,7 +159,7 @@ func resourceNifcloudNetworkUpdate(d *schema.ResourceData, meta interface{}) err
 		_, err := conn.NiftyModifyPrivateLanAttribute(&computing.NiftyModifyPrivateLanAttributeInput{
 			NetworkId: nifcloud.String(d.Id()),
 			Attribute: nifcloud.String("description"),
-			Value:     nifcloud.String(d.Get("description").(string)),
+			Value:     withDescriptionPrefix(meta, d.Get("description").(string)),
 		})
 		if err != nil {
 			return fmt.Errorf("Error NiftyModifyPrivateLanAttribute: %s", err)
@@ -239,16 +246,14 @@ func resourceNifcloudNetworkDelete(d *schema.ResourceData, meta interface{}) err
 		Refresh: func() (interface{}, string, error) {
 			_, err := conn.NiftyDeletePrivateLan(req)
 			if err != nil {
-				if apiErr, ok := err.(awserr.Error); ok {
-					if apiErr.Code() == "DependencyViolation" {
-						// There is some pending operation, so just retry
-						// in a bit.
-						return 42, "pending", nil
-					}
-
-					if apiErr.Code() == "Client.InvalidParameterNotFound.NetworkId" {
-						return 42, "destroyed", nil
-					}
+				if isNifcloudInUseErr(err) {
+					// There is some pending operation, so just retry
+					// in a bit.
+					return 42, "pending", nil
+				}
+
+				if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.NetworkId") {
+					return 42, "destroyed", nil
 				}
 
 				return 42, "failure", err
@@ -272,7 +277,7 @@ func SubnetStateRefreshFunc(conn *computing.Computing, id string) resource.State
 			NetworkId: []*string{nifcloud.String(id)},
 		})
 		if err != nil {
-			if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.NetworkId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.NetworkId") {
 				resp = nil
 			} else {
 				log.Printf("Error on SubnetStateRefresh: %s", err)
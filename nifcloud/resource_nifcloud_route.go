@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -83,7 +82,6 @@ func resourceNifcloudRouteCreate(d *schema.ResourceData, meta interface{}) error
 	if v, ok := d.GetOk("network_name"); ok {
 		createOpts.NetworkName = nifcloud.String(v.(string))
 	}
-	log.Printf("[DEBUG] Route create config: %s", createOpts)
 
 	// Create the route
 	var err error
@@ -92,6 +90,9 @@ func resourceNifcloudRouteCreate(d *schema.ResourceData, meta interface{}) error
 		_, err = conn.CreateRoute(createOpts)
 
 		if err != nil {
+			if isNifcloudTransientErr(err) {
+				return resource.RetryableError(err)
+			}
 			return resource.NonRetryableError(err)
 		}
 
@@ -132,7 +133,7 @@ func resourceNifcloudRouteRead(d *schema.ResourceData, meta interface{}) error {
 
 	route, err := resourceNifcloudRouteFindRoute(conn, routeTableId, destinationCidrBlock)
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "InvalidParameterNotFound.RouteTableId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouteTableId") {
 			log.Printf("[WARN] Route Table %q could not be found. Removing Route from state.",
 				routeTableId)
 			d.SetId("")
@@ -165,16 +166,11 @@ func resourceNifcloudRouteDelete(d *schema.ResourceData, meta interface{}) error
 	if v, ok := d.GetOk("destination_cidr_block"); ok {
 		deleteOpts.DestinationCidrBlock = nifcloud.String(v.(string))
 	}
-	log.Printf("[DEBUG] Route delete opts: %s", deleteOpts)
 
-	var resp *computing.DeleteRouteOutput
 	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
-		log.Printf("[DEBUG] Trying to delete route with opts %s", deleteOpts)
-		var err error
-		resp, err = conn.DeleteRoute(deleteOpts)
-		log.Printf("[DEBUG] Route delete result: %s", resp)
+		_, err := conn.DeleteRoute(deleteOpts)
 
-		if isNifcloudErr(err, "Client.ResourceAssociated.RouteTable", "") {
+		if isNifcloudInUseErr(err) {
 			return resource.RetryableError(err)
 		}
 		if err == nil {
@@ -184,7 +180,7 @@ func resourceNifcloudRouteDelete(d *schema.ResourceData, meta interface{}) error
 		return resource.NonRetryableError(err)
 	})
 	if isResourceTimeoutError(err) {
-		resp, err = conn.DeleteRoute(deleteOpts)
+		_, err = conn.DeleteRoute(deleteOpts)
 	}
 	if err != nil {
 		return fmt.Errorf("Error deleting route: %s", err)
@@ -202,7 +198,7 @@ func resourceNifcloudRouteExists(d *schema.ResourceData, meta interface{}) (bool
 
 	res, err := conn.DescribeRouteTables(findOpts)
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.RouteTableId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouteTableId") {
 			log.Printf("[WARN] Route Table %q could not be found.", routeTableId)
 			return false, nil
 		}
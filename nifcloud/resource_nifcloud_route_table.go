@@ -3,9 +3,9 @@ package nifcloud
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -21,6 +21,11 @@ func resourceNifcloudRouteTable() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 //		Schema: map[string]*schema.Schema{
 //			"route_table_id": {
 //				Type:     schema.TypeString,
@@ -37,7 +42,12 @@ func resourceNifcloudRouteTableCreate(d *schema.ResourceData, meta interface{})
 //	createOpts := &computing.CreateRouteTableInput{}
 //	log.Printf("[DEBUG] RouteTable create config: %#v", createOpts)
 
-	resp, err := conn.CreateRouteTable(nil) //createOpts)
+	var resp *computing.CreateRouteTableOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		resp, err = conn.CreateRouteTable(nil)
+		return err
+	}) //createOpts)
 	if err != nil {
 		return fmt.Errorf("Error creating route table: %s", err)
 	}
@@ -154,8 +164,7 @@ func resourceNifcloudRouteTableDelete(d *schema.ResourceData, meta interface{})
 		RouteTableId: nifcloud.String(d.Id()),
 	})
 	if err != nil {
-		ec2err, ok := err.(awserr.Error)
-		if ok && ec2err.Code() == "Client.InvalidParameterNotFound.RouteTableId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouteTableId") {
 			return nil
 		}
 
@@ -191,7 +200,7 @@ func resourceNifcloudRouteTableStateRefreshFunc(conn *computing.Computing, id st
 			RouteTableId: []*string{nifcloud.String(id)},
 		})
 		if err != nil {
-			if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.RouteTableId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouteTableId") {
 				resp = nil
 			} else {
 				log.Printf("Error on RouteTableStateRefresh: %s", err)
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -23,6 +22,13 @@ func resourceNifcloudRouteTableAssociation() *schema.Resource {
 			State: resourceNifcloudRouteTableAssociationImport,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"router_id": {
 				Type:     schema.TypeString,
@@ -54,14 +60,15 @@ func resourceNifcloudRouteTableAssociationCreate(d *schema.ResourceData, meta in
 
 	//var associationID string
 	var resp *computing.AssociateRouteTableOutput
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err := resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
 		var err error
 		resp, err = conn.AssociateRouteTable(&associationOpts)
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok {
-				if awsErr.Code() == "Client.InvalidParameterNotFound.RouterId" {
-					return resource.RetryableError(awsErr)
-				}
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouterId") {
+				return resource.RetryableError(err)
+			}
+			if isNifcloudTransientErr(err) {
+				return resource.RetryableError(err)
 			}
 			return resource.NonRetryableError(err)
 		}
@@ -87,21 +94,21 @@ func resourceNifcloudRouteTableAssociationRead(d *schema.ResourceData, meta inte
 
 	// Get the routing table that this association belongs to
 	rtRaw, _, err := resourceNifcloudRouteTableAssociationStateRefreshFunc(
-		conn, d.Get("route_table_id").(string))()
+		conn, d.Get("route_table_id").(string), d.Timeout(schema.TimeoutRead))()
 	if err != nil {
 		return err
 	}
 	if rtRaw == nil {
+		// The route table is gone, so is the association
+		d.SetId("")
 		return nil
 	}
 	rt := rtRaw.(*computing.RouteTableSetItem)
-	log.Printf("[INFO] route table set: %v", rt)
 	d.Set("route_table_id", rt.RouteTableId)
 
 	// Inspect that the association exists
 	found := false
 	for _, a := range rt.AssociationSet {
-		log.Printf("[INFO] Association Set: %v", a)
 		if *a.RouterId == d.Get("router_id").(string) {
 			found = true
 			d.Set("router_id", *a.RouterId)
@@ -132,7 +139,12 @@ func resourceNifcloudRouteTableAssociationUpdate(d *schema.ResourceData, meta in
 		RouteTableId:  nifcloud.String(d.Get("route_table_id").(string)),
 		Agreement:     nifcloud.Bool(false),
 	}
-	resp, err := conn.ReplaceRouteTableAssociation(req)
+	var resp *computing.ReplaceRouteTableAssociationOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutUpdate), func() error {
+		var err error
+		resp, err = conn.ReplaceRouteTableAssociation(req)
+		return err
+	})
 
 	if err != nil {
 //		ec2err, ok := err.(awserr.Error)
@@ -154,13 +166,15 @@ func resourceNifcloudRouteTableAssociationDelete(d *schema.ResourceData, meta in
 	conn := meta.(*NifcloudClient).computingconn
 
 	log.Printf("[INFO] Deleting route table association: %s", d.Id())
-	_, err := conn.DisassociateRouteTable(&computing.DisassociateRouteTableInput{
-		AssociationId: nifcloud.String(d.Id()),
-		Agreement:     nifcloud.Bool(false),
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := conn.DisassociateRouteTable(&computing.DisassociateRouteTableInput{
+			AssociationId: nifcloud.String(d.Id()),
+			Agreement:     nifcloud.Bool(false),
+		})
+		return err
 	})
 	if err != nil {
-		ec2err, ok := err.(awserr.Error)
-		if ok && ec2err.Code() == "Client.InvalidParameterNotFound.AssociationId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.AssociationId") {
 			return nil
 		}
 
@@ -222,16 +236,16 @@ func resourceNifcloudRouteTableAssociationImport(d *schema.ResourceData, meta in
 
 // resourceNifcloudRouteTableAssociationStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch
 // a RouteTableAssociation.
-func resourceNifcloudRouteTableAssociationStateRefreshFunc(conn *computing.Computing, id string) resource.StateRefreshFunc {
+func resourceNifcloudRouteTableAssociationStateRefreshFunc(conn *computing.Computing, id string, timeout time.Duration) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		var resp *computing.DescribeRouteTablesOutput
 		var err error
-		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		err = resource.Retry(timeout, func() *resource.RetryError {
 			resp, err = conn.DescribeRouteTables(&computing.DescribeRouteTablesInput{
 				RouteTableId: []*string{nifcloud.String(id)},
 			})
 			if err != nil {
-				if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.RouteTableId" {
+				if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouteTableId") {
 					resp = nil
 				} else {
 					log.Printf("Error on RouteTableStateRefresh: %s", err)
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -24,6 +23,14 @@ func resourceNifcloudRouter() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		CustomizeDiff: resourceNifcloudRouterCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"availability_zone": {
 				Type:     schema.TypeString,
@@ -91,6 +98,91 @@ func resourceNifcloudRouter() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"reboot_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "true",
+				ValidateFunc: validation.StringInSlice([]string{"force", "true", "false"}, false),
+			},
+			"reboot_on_apply": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"route_table_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"route_table_association_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"nat_table_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"nat_table_association_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"network_interface_status": routerNetworkInterfaceStatusSchema(),
+		},
+	}
+}
+
+// resourceNifcloudRouterCustomizeDiff computes reboot_on_apply on every plan,
+// true when router_type or network_interfaces change so the plan shows the
+// router is going to restart, and false otherwise. Read leaves the flag alone.
+func resourceNifcloudRouterCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+
+	reboot := diff.Get("reboot_mode").(string) != "false" &&
+		(diff.HasChange("router_type") || diff.HasChange("network_interfaces"))
+	if reboot {
+		log.Printf("[INFO] router (%s) will be restarted (reboot_mode = %s)", diff.Id(), diff.Get("reboot_mode").(string))
+		return diff.SetNew("reboot_on_apply", true)
+	}
+
+	if diff.Get("reboot_on_apply").(bool) {
+		return diff.SetNew("reboot_on_apply", false)
+	}
+
+	return nil
+}
+
+// routerNetworkInterfaceStatusSchema is the interfaces as currently attached to the router, including their DHCP settings
+func routerNetworkInterfaceStatusSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"network_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"network_name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"ipaddress": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"dhcp": {
+					Type:     schema.TypeBool,
+					Computed: true,
+				},
+				"dhcp_options_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"dhcp_config_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
 		},
 	}
 }
@@ -124,7 +216,7 @@ func resourceNifcloudRouterCreate(d *schema.ResourceData, meta interface{}) erro
 	createOpts := &computing.NiftyCreateRouterInput{
 		RouterName:       nifcloud.String(d.Get("name").(string)),
 		NetworkInterface: networkInterfaces,
-		Description:      nifcloud.String(d.Get("description").(string)),
+		Description:      withDescriptionPrefix(meta, d.Get("description").(string)),
 		Type:             nifcloud.String(d.Get("router_type").(string)),
 		AccountingType:   nifcloud.String(d.Get("accounting_type").(string)),
 		SecurityGroup:    securityGroups,
@@ -134,7 +226,12 @@ func resourceNifcloudRouterCreate(d *schema.ResourceData, meta interface{}) erro
 
 	// Create the router.
 	log.Printf("[DEBUG] Creating router")
-	resp, err := conn.NiftyCreateRouter(createOpts)
+	var resp *computing.NiftyCreateRouterOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		resp, err = conn.NiftyCreateRouter(createOpts)
+		return err
+	})
 //	var resp *computing.NiftyCreateRouterOutput
 //	err := resource.Retry(20*time.Minute, func() *resource.RetryError {
 //		var err error
@@ -164,13 +261,14 @@ func resourceNifcloudRouterCreate(d *schema.ResourceData, meta interface{}) erro
 	router := resp.Router
 	d.SetId(*router.RouterId)
 	log.Printf("[INFO] router ID: %s", *router.RouterId)
+	d.Set("reboot_on_apply", false)
 
 	// Wait for the router to be available.
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{"pending", "warning"},
 		Target:     []string{"available"},
 		Refresh:    routerRefreshFunc(conn, *router.RouterId),
-		Timeout:    15 * time.Minute,
+		Timeout:    d.Timeout(schema.TimeoutCreate),
 		Delay:      10 * time.Second,
 		MinTimeout: 3 * time.Second,
 	}
@@ -196,7 +294,7 @@ func routerRefreshFunc(conn *computing.Computing, routerID string) resource.Stat
 			Filter: []*computing.RequestFilterStruct{routerFilter},
 		})
 		if err != nil {
-			if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.RouterId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouterId") {
 				resp = nil
 			} else {
 				log.Printf("Error on routerRefresh: %s", err)
@@ -246,7 +344,7 @@ func resourceNifcloudRouterRead(d *schema.ResourceData, meta interface{}) error
 		Filter: []*computing.RequestFilterStruct{routerFilter},
 	})
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.RouterId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouterId") {
 			d.SetId("")
 			return nil
 		}
@@ -254,11 +352,15 @@ func resourceNifcloudRouterRead(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
+	if len(resp.RouterSet) == 0 {
+		log.Printf("[WARN] router (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if len(resp.RouterSet) != 1 {
 		return fmt.Errorf("Error finding router: %s", d.Id())
 	}
 
-	log.Printf("[DEBUG] router describe %v", resp)
 	if *resp.RouterSet[0].State == "deleted" {
 		log.Printf("[INFO] router is in `deleted` state: %s", d.Id())
 		d.SetId("")
@@ -267,7 +369,7 @@ func resourceNifcloudRouterRead(d *schema.ResourceData, meta interface{}) error
 
 	router := resp.RouterSet[0]
 	d.Set("name", router.RouterName)
-	d.Set("description", router.Description)
+	d.Set("description", trimDescriptionPrefix(meta, router.Description))
 	d.Set("router_type", router.Type)
 	d.Set("accounting_type", router.NextMonthAccountingType)
 	d.Set("availability_zone", router.AvailabilityZone)
@@ -282,6 +384,35 @@ func resourceNifcloudRouterRead(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
+	if _, ok := d.GetOk("reboot_mode"); !ok {
+		d.Set("reboot_mode", "true")
+	}
+
+	return setNifcloudRouterAttachments(d, router)
+}
+
+// setNifcloudRouterAttachments sets the route table, NAT table and interfaces currently attached to the router
+func setNifcloudRouterAttachments(d *schema.ResourceData, router *computing.RouterSetItem) error {
+	d.Set("route_table_id", router.RouteTableId)
+	d.Set("route_table_association_id", router.RouteTableAssociationId)
+	d.Set("nat_table_id", router.NatTableId)
+	d.Set("nat_table_association_id", router.NatTableAssociationId)
+
+	interfaces := make([]map[string]interface{}, 0, len(router.NetworkInterfaceSet))
+	for _, ni := range router.NetworkInterfaceSet {
+		interfaces = append(interfaces, map[string]interface{}{
+			"network_id":      nifcloud.StringValue(ni.NetworkId),
+			"network_name":    nifcloud.StringValue(ni.NetworkName),
+			"ipaddress":       nifcloud.StringValue(ni.IpAddress),
+			"dhcp":            nifcloud.BoolValue(ni.Dhcp),
+			"dhcp_options_id": nifcloud.StringValue(ni.DhcpOptionsId),
+			"dhcp_config_id":  nifcloud.StringValue(ni.DhcpConfigId),
+		})
+	}
+	if err := d.Set("network_interface_status", interfaces); err != nil {
+		return fmt.Errorf("error setting network_interface_status: %s", err)
+	}
+
 	return nil
 }
 
@@ -295,7 +426,7 @@ func resourceNifcloudRouterUpdate(d *schema.ResourceData, meta interface{}) erro
 		input := computing.NiftyModifyRouterAttributeInput{
 			RouterId: nifcloud.String(d.Id()),
 			Attribute:    nifcloud.String("description"),
-			Value:        nifcloud.String(d.Get("description").(string)),
+			Value:        withDescriptionPrefix(meta, d.Get("description").(string)),
 			Agreement:    nifcloud.Bool(false),
 		}
 		_, err := conn.NiftyModifyRouterAttribute(&input)
@@ -320,7 +451,7 @@ func resourceNifcloudRouterUpdate(d *schema.ResourceData, meta interface{}) erro
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    routerRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -349,7 +480,7 @@ func resourceNifcloudRouterUpdate(d *schema.ResourceData, meta interface{}) erro
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    routerRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -378,7 +509,7 @@ func resourceNifcloudRouterUpdate(d *schema.ResourceData, meta interface{}) erro
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    routerRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -395,6 +526,7 @@ func resourceNifcloudRouterUpdate(d *schema.ResourceData, meta interface{}) erro
 			RouterId: nifcloud.String(d.Id()),
 			Attribute:    nifcloud.String("type"),
 			Value:        nifcloud.String(d.Get("router_type").(string)),
+			NiftyReboot:  nifcloud.String(d.Get("reboot_mode").(string)),
 			Agreement:    nifcloud.Bool(false),
 		}
 		_, err := conn.NiftyModifyRouterAttribute(&input)
@@ -407,7 +539,7 @@ func resourceNifcloudRouterUpdate(d *schema.ResourceData, meta interface{}) erro
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    routerRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -439,7 +571,7 @@ func resourceNifcloudRouterUpdate(d *schema.ResourceData, meta interface{}) erro
 		input := computing.NiftyUpdateRouterNetworkInterfacesInput{
 			RouterId:         nifcloud.String(d.Id()),
 			NetworkInterface: networkInterfaces,
-			NiftyReboot:      nifcloud.String("true"),
+			NiftyReboot:      nifcloud.String(d.Get("reboot_mode").(string)),
 			Agreement:        nifcloud.Bool(false),
 		}
 		_, err := conn.NiftyUpdateRouterNetworkInterfaces(&input)
@@ -452,7 +584,7 @@ func resourceNifcloudRouterUpdate(d *schema.ResourceData, meta interface{}) erro
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    routerRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -476,12 +608,11 @@ func resourceNifcloudRouterDelete(d *schema.ResourceData, meta interface{}) erro
 	request := &computing.NiftyDeleteRouterInput{
 		RouterId: nifcloud.String(d.Id()),
 	}
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
-		resp, err := conn.NiftyDeleteRouter(request)
-		log.Printf("[DEBUG] deleting router %v", resp)
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := conn.NiftyDeleteRouter(request)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.RouterId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouterId") {
 				return nil
 			}
 			return resource.RetryableError(err)
@@ -494,7 +625,7 @@ func resourceNifcloudRouterDelete(d *schema.ResourceData, meta interface{}) erro
 		RouterId: nifcloud.String(d.Id()),
 	})
 	if err != nil {
-		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.RouterId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouterId") {
 			return nil
 		}
 		return fmt.Errorf("[ERROR] Error deleting router: %s", err)
@@ -508,12 +639,11 @@ func resourceNifcloudRouterDelete(d *schema.ResourceData, meta interface{}) erro
 	input := &computing.NiftyDescribeRoutersInput{
 		Filter: []*computing.RequestFilterStruct{routerFilter},
 	}
-	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		resp, err := conn.NiftyDescribeRouters(input)
-		log.Printf("[DEBUG] delete after describe 001 %v", resp)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.RouterId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.RouterId") {
 				return nil
 			}
 			return resource.RetryableError(err)
@@ -529,7 +659,6 @@ func resourceNifcloudRouterDelete(d *schema.ResourceData, meta interface{}) erro
 	if isResourceTimeoutError(err) {
 		var resp *computing.NiftyDescribeRoutersOutput
 		resp, err = conn.NiftyDescribeRouters(input)
-		log.Printf("[DEBUG] delete after describe 002 %v", resp)
 
 		if err != nil {
 			return checkRouterDeleteResponse(resp, d.Id())
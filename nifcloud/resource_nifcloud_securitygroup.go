@@ -6,7 +6,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"log"
 	"time"
@@ -38,6 +37,7 @@ func resourceNifcloudSecurityGroup() *schema.Resource {
 				return nil, fmt.Errorf("Error Import resource: %s", d.Id())
 			},
 		},
+		CustomizeDiff: securityGroupRuleStatusCustomizeDiff,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(20 * time.Minute),
@@ -111,6 +111,7 @@ func resourceNifcloudSecurityGroup() *schema.Resource {
 				},
 			},
 
+			"rule_status": securityGroupRuleStatusSchema(),
 		},
 	}
 }
@@ -120,10 +121,15 @@ func resourceNifcloudSecurityGroupCreate(d *schema.ResourceData, meta interface{
 
 	input := computing.CreateSecurityGroupInput{
 		GroupName:        nifcloud.String(d.Get("name").(string)),
-		GroupDescription: nifcloud.String(d.Get("description").(string)),
+		GroupDescription: withDescriptionPrefix(meta, d.Get("description").(string)),
 	}
 
-	securitygroup, err := conn.CreateSecurityGroup(&input)
+	var securitygroup *computing.CreateSecurityGroupOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		securitygroup, err = conn.CreateSecurityGroup(&input)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error CreateSecurityGroupInput: %s", err)
 	}
@@ -146,7 +152,7 @@ func resourceNifcloudSecurityGroupCreate(d *schema.ResourceData, meta interface{
 	if group.GroupName != nil && *group.GroupName != "" {
 		log.Printf("[DEBUG] Authorize default rule for Security Group for %s", d.Id())
 
-		ipPermissions := setSecurityGroupRule(d.Get("rules"))
+		ipPermissions := setSecurityGroupRule(meta, d.Get("rules"))
 //		log.Printf("[INFO] **********************************\n ipPermissions : %v\n ***************************", ipPermissions)
 		if ipPermissions != nil && len(ipPermissions) != 0 {
 			req := computing.AuthorizeSecurityGroupIngressInput{
@@ -159,6 +165,16 @@ func resourceNifcloudSecurityGroupCreate(d *schema.ResourceData, meta interface{
 					"Error authorizing default rule for Security Group (%s): %s",
 					d.Id(), err)
 			}
+
+			statuses, err := waitForSecurityGroupRulesApplied(conn, d.Id(), ipPermissions, d.Timeout(schema.TimeoutCreate))
+			if statuses == nil && err != nil {
+				return fmt.Errorf("Error waiting for Security Group (%s) rules to be applied: %s", d.Id(), err)
+			}
+			if err != nil {
+				// the group is created, keep the pending rules for the next apply
+				// rather than tainting it
+				log.Printf("[WARN] %s, they will be checked again on the next apply", err)
+			}
 		}
 	}
 
@@ -185,7 +201,7 @@ func resourceNifcloudSecurityGroupDelete(d *schema.ResourceData, meta interface{
 	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, err := conn.DeleteSecurityGroup(&input)
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.SecurityGroup" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroup") {
 				return nil
 			}
 			return resource.RetryableError(err)
@@ -194,7 +210,7 @@ func resourceNifcloudSecurityGroupDelete(d *schema.ResourceData, meta interface{
 	})
 	if isResourceTimeoutError(err) {
 		_, err = conn.DeleteSecurityGroup(&input)
-		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.SecurityGroup" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroup") {
 			return nil
 		}
 	}
@@ -211,7 +227,7 @@ func resourceNifcloudSecurityGroupUpdate(d *schema.ResourceData, meta interface{
 	if d.HasChange("description") {
 		_, err := conn.UpdateSecurityGroup(&computing.UpdateSecurityGroupInput{
 			GroupName:              nifcloud.String(d.Get("name").(string)),
-			GroupDescriptionUpdate: nifcloud.String(d.Get("description").(string)),
+			GroupDescriptionUpdate: withDescriptionPrefix(meta, d.Get("description").(string)),
 		})
 		if err != nil {
 			return fmt.Errorf("Error UpdateSecurityGroup: %s", err)
@@ -244,9 +260,9 @@ func resourceNifcloudSecurityGroupUpdate(d *schema.ResourceData, meta interface{
 
 	if d.HasChange("rules") {
 		before, after := d.GetChange("rules")
-		ipPermissionsOld := setSecurityGroupRule(before)
+		ipPermissionsOld := setSecurityGroupRule(meta, before)
 //		log.Printf("[INFO] **********************************\n before ipPermissions : %v\n ***************************", ipPermissionsOld)
-		ipPermissionsNew := setSecurityGroupRule(after)
+		ipPermissionsNew := setSecurityGroupRule(meta, after)
 //		log.Printf("[INFO] **********************************\n after ipPermissions : %v\n ***************************", ipPermissionsNew)
 		if ipPermissionsOld != nil {
 			req := computing.RevokeSecurityGroupIngressInput{
@@ -272,6 +288,18 @@ func resourceNifcloudSecurityGroupUpdate(d *schema.ResourceData, meta interface{
 					d.Id(), err)
 			}
 		}
+	} else if err := resumeSecurityGroupRules(conn, d.Id(), setSecurityGroupRule(meta, d.Get("rules")), d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return fmt.Errorf(
+			"Error authorizing default ingress rule for Security Group (%s): %s",
+			d.Id(), err)
+	}
+
+	statuses, err := waitForSecurityGroupRulesApplied(conn, d.Id(), setSecurityGroupRule(meta, d.Get("rules")), d.Timeout(schema.TimeoutUpdate))
+	if statuses != nil {
+		d.Set("rule_status", statuses)
+	}
+	if err != nil {
+		return fmt.Errorf("Error waiting for Security Group (%s) rules to be applied: %s", d.Id(), err)
 	}
 
 	return resourceNifcloudSecurityGroupRead(d, meta)
@@ -286,8 +314,7 @@ func resourceNifcloudSecurityGroupRead(d *schema.ResourceData, meta interface{})
 
 	out, err := conn.DescribeSecurityGroups(&input)
 	if err != nil {
-		awsErr, ok := err.(awserr.Error)
-		if ok && awsErr.Code() == "Client.InvalidParameterNotFound.SecurityGroup" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroup") {
 			d.SetId("")
 			return nil
 		}
@@ -317,11 +344,9 @@ func SGStateRefreshFunc(conn *computing.Computing, id string) resource.StateRefr
 		}
 		resp, err := conn.DescribeSecurityGroups(&req)
 		if err != nil {
-			if ec2err, ok := err.(awserr.Error); ok {
-				if ec2err.Code() == "Client.InvalidParameterNotFound.SecurityGroup" {
-					resp = nil
-					err = nil
-				}
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroup") {
+				resp = nil
+				err = nil
 			}
 
 			if err != nil {
@@ -340,23 +365,31 @@ func SGStateRefreshFunc(conn *computing.Computing, id string) resource.StateRefr
 }
 
 func setSecurityGroupResourceData(d *schema.ResourceData, meta interface{}, out *computing.DescribeSecurityGroupsOutput) error {
+	if len(out.SecurityGroupInfo) == 0 {
+		log.Printf("[WARN] SecurityGroup (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	securitygroup := out.SecurityGroupInfo[0]
 
 	d.Set("name", securitygroup.GroupName)
-	d.Set("description", securitygroup.GroupDescription)
+	d.Set("description", trimDescriptionPrefix(meta, securitygroup.GroupDescription))
 //	d.Set("rules", securitygroup.IpPermissions)
 	d.Set("rules", d.Get("rules"))
+	if err := d.Set("rule_status", securityGroupRuleStatuses(securitygroup, setSecurityGroupRule(meta, d.Get("rules")))); err != nil {
+		return fmt.Errorf("Error setting rule_status: %s", err)
+	}
 
 	return nil
 }
 
-func setSecurityGroupRule(permissions interface{}) []*computing.RequestIpPermissionsStruct {
+func setSecurityGroupRule(meta interface{}, permissions interface{}) []*computing.RequestIpPermissionsStruct {
 	var ipPermissions []*computing.RequestIpPermissionsStruct
 	for _, ip := range permissions.(*schema.Set).List() {
 		ipPermission := &computing.RequestIpPermissionsStruct{}
 		if v, ok := ip.(map[string]interface{}); ok {
 			if v["description"].(string) != "" {
-				ipPermission.SetDescription(v["description"].(string))
+				ipPermission.Description = withDescriptionPrefix(meta, v["description"].(string))
 			}
 			
 			if v["from_port"].(int) > 0 {
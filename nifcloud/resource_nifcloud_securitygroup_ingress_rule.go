@@ -0,0 +1,351 @@
+package nifcloud
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+)
+
+// resourceNifcloudSecurityGroupIngressRule manages one rule of a group.
+// AuthorizeSecurityGroupIngress takes both directions, so inout may be IN or OUT.
+func resourceNifcloudSecurityGroupIngressRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceNifcloudSecurityGroupIngressRuleCreate,
+		Read:   resourceNifcloudSecurityGroupIngressRuleRead,
+		Update: resourceNifcloudSecurityGroupIngressRuleUpdate,
+		Delete: resourceNifcloudSecurityGroupIngressRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceNifcloudSecurityGroupIngressRuleImport,
+		},
+		CustomizeDiff: securityGroupIngressRuleCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"security_group_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 15),
+			},
+			"inout": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"IN", "OUT"}, false),
+			},
+			"protocol": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.EqualFold(old, new)
+				},
+			},
+			"from_port": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"to_port": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"cidr_ip": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"source_security_group_name"},
+			},
+			"source_security_group_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"cidr_ip"},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceNifcloudSecurityGroupIngressRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+	sgID := d.Get("security_group_name").(string)
+
+	if d.Get("cidr_ip").(string) == "" && d.Get("source_security_group_name").(string) == "" {
+		return fmt.Errorf("One of cidr_ip or source_security_group_name must be set")
+	}
+
+	awsMutexKV.Lock(sgID)
+	defer awsMutexKV.Unlock(sgID)
+
+	perm := expandSecurityGroupIngressRule(d, meta)
+	id := securityGroupIngressRuleID(d)
+
+	sg, err := findResourceSecurityGroup(conn, sgID)
+	if err != nil {
+		return fmt.Errorf("Error finding Security Group (%s): %s", sgID, err)
+	}
+
+	// the same rule set inline on nifcloud_securitygroup (or by
+	// nifcloud_securitygroup_rule) would be revoked by whichever applies next
+	if findRuleMatch(securityGroupRuleWithoutDescription(perm), sg.IpPermissions) != nil {
+		return fmt.Errorf(
+			"Security Group Rule (%s) already exists in Group %s, remove it from the group's inline rules "+
+				"or import it with: terraform import nifcloud_securitygroup_ingress_rule.<name> %s",
+			id, sgID, id)
+	}
+
+	req := computing.AuthorizeSecurityGroupIngressInput{
+		GroupName:     nifcloud.String(sgID),
+		IpPermissions: []*computing.RequestIpPermissionsStruct{perm},
+	}
+
+	// a replacement comes right after the revoke of the old rule
+	if err := authorizeSecurityGroupIngress(conn, &req, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return fmt.Errorf("Error authorizing rule for Security Group (%s): %s", sgID, err)
+	}
+
+	d.SetId(id)
+
+	statuses, err := waitForSecurityGroupRulesApplied(conn, sgID, req.IpPermissions, d.Timeout(schema.TimeoutCreate))
+	if statuses == nil && err != nil {
+		return fmt.Errorf("Error waiting for Security Group Rule (%s) to be applied: %s", id, err)
+	}
+	if err != nil {
+		// same as nifcloud_securitygroup_rule, keep it pending for the next apply
+		log.Printf("[WARN] %s, it will be checked again on the next apply", err)
+	}
+
+	return resourceNifcloudSecurityGroupIngressRuleRead(d, meta)
+}
+
+func resourceNifcloudSecurityGroupIngressRuleRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+	sgID := d.Get("security_group_name").(string)
+
+	sg, err := findResourceSecurityGroup(conn, sgID)
+	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroup") {
+			log.Printf("[WARN] Security Group (%s) not found, removing Security Group Rule (%s) from state", sgID, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error finding security group (%s) for rule (%s): %s", sgID, d.Id(), err)
+	}
+
+	perm := securityGroupRuleWithoutDescription(expandSecurityGroupIngressRule(d, meta))
+	rule := findRuleMatch(perm, sg.IpPermissions)
+
+	if rule == nil && securityGroupApplied(sg) {
+		log.Printf("[WARN] Security Group Rule (%s) not found in Group %s, removing from state", d.Id(), sgID)
+		d.SetId("")
+		return nil
+	}
+
+	status := securityGroupRuleStatusPending
+	if rule != nil {
+		log.Printf("[DEBUG] Found rule for Security Group Rule (%s): %s", d.Id(), rule)
+		d.Set("description", trimDescriptionPrefix(meta, rule.Description))
+		if securityGroupApplied(sg) {
+			status = securityGroupRuleStatusApplied
+		}
+	}
+	d.Set("status", status)
+
+	return nil
+}
+
+// resourceNifcloudSecurityGroupIngressRuleUpdate changes the description by
+// replacing the rule, and otherwise picks up a rule left pending.
+func resourceNifcloudSecurityGroupIngressRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+	sgID := d.Get("security_group_name").(string)
+
+	awsMutexKV.Lock(sgID)
+	defer awsMutexKV.Unlock(sgID)
+
+	perm := expandSecurityGroupIngressRule(d, meta)
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	if d.HasChange("description") {
+		o, _ := d.GetChange("description")
+		old := securityGroupRuleWithoutDescription(perm)
+		if o.(string) != "" {
+			old.Description = withDescriptionPrefix(meta, o.(string))
+		}
+
+		_, err := conn.RevokeSecurityGroupIngress(&computing.RevokeSecurityGroupIngressInput{
+			GroupName:     nifcloud.String(sgID),
+			IpPermissions: []*computing.RequestIpPermissionsStruct{old},
+		})
+		if err != nil && !isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroupIngress") {
+			return fmt.Errorf("Error deleting rule for Security Group (%s): %s", sgID, err)
+		}
+
+		// the group refuses new rules until the revoke is applied
+		if err := waitForSecurityGroupApplied(conn, sgID, timeout); err != nil {
+			return fmt.Errorf("Error waiting for rule of Security Group (%s) to be revoked: %s", sgID, err)
+		}
+
+		err = authorizeSecurityGroupIngress(conn, &computing.AuthorizeSecurityGroupIngressInput{
+			GroupName:     nifcloud.String(sgID),
+			IpPermissions: []*computing.RequestIpPermissionsStruct{perm},
+		}, timeout)
+		if err != nil {
+			// the old rule is gone, drop it from the state so the next apply adds it again
+			d.SetId("")
+			return fmt.Errorf("Error authorizing rule for Security Group (%s): %s", sgID, err)
+		}
+	} else if err := resumeSecurityGroupRules(conn, sgID, []*computing.RequestIpPermissionsStruct{perm}, timeout); err != nil {
+		return fmt.Errorf("Error authorizing rule for Security Group (%s): %s", sgID, err)
+	}
+
+	_, err := waitForSecurityGroupRulesApplied(conn, sgID, []*computing.RequestIpPermissionsStruct{perm}, timeout)
+	if err != nil {
+		return fmt.Errorf("Error waiting for Security Group Rule (%s) to be applied: %s", d.Id(), err)
+	}
+
+	return resourceNifcloudSecurityGroupIngressRuleRead(d, meta)
+}
+
+func resourceNifcloudSecurityGroupIngressRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*NifcloudClient).computingconn
+	sgID := d.Get("security_group_name").(string)
+
+	awsMutexKV.Lock(sgID)
+	defer awsMutexKV.Unlock(sgID)
+
+	req := computing.RevokeSecurityGroupIngressInput{
+		GroupName:     nifcloud.String(sgID),
+		IpPermissions: []*computing.RequestIpPermissionsStruct{expandSecurityGroupIngressRule(d, meta)},
+	}
+
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := conn.RevokeSecurityGroupIngress(&req)
+		if err != nil {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroupIngress") {
+				return nil
+			}
+			return resource.RetryableError(err)
+		}
+		return nil
+	})
+	if isResourceTimeoutError(err) {
+		_, err = conn.RevokeSecurityGroupIngress(&req)
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroupIngress") {
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("Error deleting rule for Security Group (%s): %s", sgID, err)
+	}
+
+	return nil
+}
+
+// resourceNifcloudSecurityGroupIngressRuleImport takes the ID the resource
+// would have computed, <group>_<inout>_<protocol>_<from_port>_<to_port>_<cidr or group>
+func resourceNifcloudSecurityGroupIngressRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "_")
+	if len(parts) != 6 {
+		return nil, fmt.Errorf(
+			"Unexpected format of ID (%s), expected <group>_<inout>_<protocol>_<from_port>_<to_port>_<cidr or group>",
+			d.Id())
+	}
+
+	fromPort, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid from_port in ID (%s): %s", d.Id(), err)
+	}
+	toPort, err := strconv.Atoi(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid to_port in ID (%s): %s", d.Id(), err)
+	}
+
+	d.Set("security_group_name", parts[0])
+	d.Set("inout", parts[1])
+	d.Set("protocol", parts[2])
+	d.Set("from_port", fromPort)
+	d.Set("to_port", toPort)
+	if strings.Contains(parts[5], "/") || net.ParseIP(parts[5]) != nil {
+		d.Set("cidr_ip", parts[5])
+	} else {
+		d.Set("source_security_group_name", parts[5])
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// securityGroupIngressRuleCustomizeDiff plans an update while the rule is
+// still pending, so the next apply picks it up rather than recreating it.
+func securityGroupIngressRuleCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+	if diff.HasChange("description") || diff.Get("status").(string) == securityGroupRuleStatusPending {
+		return diff.SetNewComputed("status")
+	}
+
+	return nil
+}
+
+func expandSecurityGroupIngressRule(d *schema.ResourceData, meta interface{}) *computing.RequestIpPermissionsStruct {
+	return expandIPPermItem(meta, map[string]interface{}{
+		"protocol":        d.Get("protocol"),
+		"from_port":       d.Get("from_port"),
+		"to_port":         d.Get("to_port"),
+		"inout":           d.Get("inout"),
+		"description":     d.Get("description"),
+		"cidr_blocks":     d.Get("cidr_ip"),
+		"security_groups": d.Get("source_security_group_name"),
+	})
+}
+
+// securityGroupRuleWithoutDescription copies perm without its description,
+// a rule is the same rule whatever it is described as.
+func securityGroupRuleWithoutDescription(perm *computing.RequestIpPermissionsStruct) *computing.RequestIpPermissionsStruct {
+	p := *perm
+	p.Description = nil
+	return &p
+}
+
+// securityGroupIngressRuleID is built from the arguments that identify the
+// rule, with the protocol as configured (SSH rather than TCP/22) so that an
+// imported rule reads back the same arguments.
+func securityGroupIngressRuleID(d *schema.ResourceData) string {
+	source := d.Get("cidr_ip").(string)
+	if source == "" {
+		source = d.Get("source_security_group_name").(string)
+	}
+
+	return strings.Join([]string{
+		d.Get("security_group_name").(string),
+		d.Get("inout").(string),
+		strings.ToUpper(d.Get("protocol").(string)),
+		strconv.Itoa(d.Get("from_port").(int)),
+		strconv.Itoa(d.Get("to_port").(int)),
+		source,
+	}, "_")
+}
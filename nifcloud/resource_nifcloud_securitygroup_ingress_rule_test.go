@@ -0,0 +1,138 @@
+package nifcloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestSecurityGroupIngressRuleID(t *testing.T) {
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			raw: map[string]interface{}{
+				"security_group_name": "web",
+				"inout":               "IN",
+				"protocol":            "tcp",
+				"from_port":           8080,
+				"to_port":             8081,
+				"cidr_ip":             "10.0.0.0/16",
+			},
+			expected: "web_IN_TCP_8080_8081_10.0.0.0/16",
+		},
+		{
+			// named protocols keep their name, the ports stay as configured
+			raw: map[string]interface{}{
+				"security_group_name":        "web",
+				"inout":                      "IN",
+				"protocol":                   "ssh",
+				"source_security_group_name": "bastion",
+			},
+			expected: "web_IN_SSH_0_0_bastion",
+		},
+		{
+			raw: map[string]interface{}{
+				"security_group_name": "db",
+				"inout":               "OUT",
+				"protocol":            "ANY",
+				"cidr_ip":             "192.168.0.1",
+			},
+			expected: "db_OUT_ANY_0_0_192.168.0.1",
+		},
+	}
+
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceNifcloudSecurityGroupIngressRule().Schema, tc.raw)
+		if id := securityGroupIngressRuleID(d); id != tc.expected {
+			t.Errorf("securityGroupIngressRuleID(%v) = %q, want %q", tc.raw, id, tc.expected)
+		}
+	}
+}
+
+func TestResourceNifcloudSecurityGroupIngressRuleImport(t *testing.T) {
+	cases := []struct {
+		id       string
+		expected map[string]interface{}
+	}{
+		{
+			id: "web_IN_TCP_8080_8081_10.0.0.0/16",
+			expected: map[string]interface{}{
+				"security_group_name":        "web",
+				"inout":                      "IN",
+				"protocol":                   "TCP",
+				"from_port":                  8080,
+				"to_port":                    8081,
+				"cidr_ip":                    "10.0.0.0/16",
+				"source_security_group_name": "",
+			},
+		},
+		{
+			id: "web_IN_SSH_0_0_bastion",
+			expected: map[string]interface{}{
+				"security_group_name":        "web",
+				"inout":                      "IN",
+				"protocol":                   "SSH",
+				"from_port":                  0,
+				"to_port":                    0,
+				"cidr_ip":                    "",
+				"source_security_group_name": "bastion",
+			},
+		},
+		{
+			// a single address without a prefix length is still a CIDR
+			id: "db_OUT_ANY_0_0_192.168.0.1",
+			expected: map[string]interface{}{
+				"security_group_name":        "db",
+				"inout":                      "OUT",
+				"protocol":                   "ANY",
+				"cidr_ip":                    "192.168.0.1",
+				"source_security_group_name": "",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		d := resourceNifcloudSecurityGroupIngressRule().TestResourceData()
+		d.SetId(tc.id)
+
+		result, err := resourceNifcloudSecurityGroupIngressRuleImport(d, nil)
+		if err != nil {
+			t.Errorf("import of %q: unexpected error: %s", tc.id, err)
+			continue
+		}
+		if len(result) != 1 {
+			t.Errorf("import of %q: expected 1 resource, got %d", tc.id, len(result))
+			continue
+		}
+
+		for k, v := range tc.expected {
+			if got := result[0].Get(k); got != v {
+				t.Errorf("import of %q: %s = %#v, want %#v", tc.id, k, got, v)
+			}
+		}
+
+		// the imported arguments give back the ID they were imported with
+		if id := securityGroupIngressRuleID(result[0]); id != tc.id {
+			t.Errorf("import of %q: ID computed from the imported arguments is %q", tc.id, id)
+		}
+	}
+}
+
+func TestResourceNifcloudSecurityGroupIngressRuleImport_invalid(t *testing.T) {
+	for _, id := range []string{
+		"web",
+		"web_IN_TCP_80_80",
+		"web_IN_TCP_80_80_10.0.0.0/16_extra",
+		"web_IN_TCP_http_80_10.0.0.0/16",
+		"web_IN_TCP_80_http_10.0.0.0/16",
+	} {
+		d := resourceNifcloudSecurityGroupIngressRule().TestResourceData()
+		d.SetId(id)
+
+		if _, err := resourceNifcloudSecurityGroupIngressRuleImport(d, nil); err == nil {
+			t.Errorf("import of %q: expected an error", id)
+		}
+	}
+}
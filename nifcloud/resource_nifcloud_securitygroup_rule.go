@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 	"log"
 	"time"
@@ -22,6 +21,13 @@ func resourceNifcloudSecurityGroupRule() *schema.Resource {
 		Update: resourceNifcloudSecurityGroupRuleUpdate,
 		Delete: resourceNifcloudSecurityGroupRuleDelete,
 		Importer: &schema.ResourceImporter{},
+		CustomizeDiff: securityGroupRuleStatusCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		SchemaVersion: 1,
 
@@ -78,6 +84,7 @@ func resourceNifcloudSecurityGroupRule() *schema.Resource {
 				},
 			},
 
+			"rule_status": securityGroupRuleStatusSchema(),
 		},
 	}
 }
@@ -97,67 +104,48 @@ func resourceNifcloudSecurityGroupRuleCreate(d *schema.ResourceData, meta interf
 		return err
 	}
 
-	perm := expandIPPerm(d.Get("rules"))
+	perm := expandIPPerm(meta, d.Get("rules"))
 	if perm == nil {
 		return nil
 	}
 
-//	log.Printf("[DEBUG] Authorize rule for Security Group for %s", sgID)
-//	log.Printf("[INFO] **********************************\n ipPermissions : %v\n ***************************", perm)
-	req := computing.AuthorizeSecurityGroupIngressInput{
-		GroupName:     nifcloud.String(sgID),
-		IpPermissions: perm,
-	}
-
-	if _, err = conn.AuthorizeSecurityGroupIngress(&req); err != nil {
-		return fmt.Errorf(
-			"Error authorizing rule for Security Group (%s): %s",
-			sgID, err)
-	}
-
-	var rules []*computing.IpPermissionsSetItem
 	id := ipPermissionIDHash(sgID, perm[0])
 	log.Printf("[DEBUG] Computed group rule ID %s", id)
 
-	d.SetId(id)
-
-	err = resource.Retry(10*time.Minute, func() *resource.RetryError {
-		sg, err := findResourceSecurityGroup(conn, sgID)
-
-		if err != nil {
-			log.Printf("[DEBUG] Error finding Security Group (%s) for Rule (%s): %s", sgID, id, err)
-			return resource.NonRetryableError(err)
-		}
-
-		rules = sg.IpPermissions
-	
-		rule := findRuleMatch(perm[0], rules)
-		if rule == nil {
-			log.Printf("[DEBUG] Unable to find matching Security Group Rule (%s) for Group %s",
-				id, sgID)
-			return resource.RetryableError(fmt.Errorf("No match found"))
+	// only authorize what the group doesn't have yet, a rule already set
+	// (e.g. inline on nifcloud_securitygroup) would fail the whole request
+	if missing := missingSecurityGroupRules(sg, perm); len(missing) > 0 {
+		req := computing.AuthorizeSecurityGroupIngressInput{
+			GroupName:     nifcloud.String(sgID),
+			IpPermissions: missing,
 		}
 
-		log.Printf("[DEBUG] Found rule for Security Group Rule (%s): %s", id, rule)
-		return nil
-	})
-	if isResourceTimeoutError(err) {
-		sg, err := findResourceSecurityGroup(conn, sgID)
+		err = retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+			_, err := conn.AuthorizeSecurityGroupIngress(&req)
+			return err
+		})
 		if err != nil {
-			return fmt.Errorf("Error finding security group: %s", err)
+			return fmt.Errorf(
+				"Error authorizing rule for Security Group (%s): %s",
+				sgID, err)
 		}
+	} else {
+		log.Printf("[DEBUG] Security Group Rule (%s) already exists in Group %s", id, sgID)
+	}
 
-		rules = sg.IpPermissions
+	d.SetId(id)
 
-		rule := findRuleMatch(perm[0], rules)
-		if rule == nil {
-			return fmt.Errorf("Error finding matching security group rule: %s", err)
-		}
+	statuses, err := waitForSecurityGroupRulesApplied(conn, sgID, perm, d.Timeout(schema.TimeoutCreate))
+	if statuses == nil && err != nil {
+		return fmt.Errorf("Error finding matching Security Group Rule (%s) for Group %s: %s", id, sgID, err)
 	}
 	if err != nil {
-		return fmt.Errorf("Error finding matching Security Group Rule (%s) for Group %s", id, sgID)
+		// failing here would taint the rule and authorize it once more,
+		// keep it pending so the next apply picks it up
+		log.Printf("[WARN] %s, it will be checked again on the next apply", err)
 	}
-	
+	d.Set("rule_status", statuses)
+
 	return nil
 }
 
@@ -172,7 +160,7 @@ func resourceNifcloudSecurityGroupRuleDelete(d *schema.ResourceData, meta interf
 	if err != nil {
 		return err
 	}
-	perm := expandIPPerm(d.Get("rules"))
+	perm := expandIPPerm(meta, d.Get("rules"))
 	if perm == nil {
 		return err
 	}
@@ -182,10 +170,10 @@ func resourceNifcloudSecurityGroupRuleDelete(d *schema.ResourceData, meta interf
 		IpPermissions: perm,
 	}
 
-	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, err := conn.RevokeSecurityGroupIngress(&req)
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.SecurityGroupIngress" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroupIngress") {
 				return nil
 			}
 			return resource.RetryableError(err)
@@ -194,7 +182,7 @@ func resourceNifcloudSecurityGroupRuleDelete(d *schema.ResourceData, meta interf
 	})
 	if isResourceTimeoutError(err) {
 		_, err = conn.RevokeSecurityGroupIngress(&req)
-		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.SecurityGroupIngress" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroupIngress") {
 			return nil
 		}
 	}
@@ -214,12 +202,12 @@ func resourceNifcloudSecurityGroupRuleUpdate(d *schema.ResourceData, meta interf
 	awsMutexKV.Lock(sgID)
 	defer awsMutexKV.Unlock(sgID)
 
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
 	if d.HasChange("rules") {
 		before, after := d.GetChange("rules")
-		ipPermissionsOld := expandIPPerm(before)
-		log.Printf("[INFO] **********************************\n before ipPermissions : %v\n ***************************", ipPermissionsOld)
-		ipPermissionsNew := expandIPPerm(after)
-		log.Printf("[INFO] **********************************\n after ipPermissions : %v\n ***************************", ipPermissionsNew)
+		ipPermissionsOld := expandIPPerm(meta, before)
+		ipPermissionsNew := expandIPPerm(meta, after)
 		if ipPermissionsOld != nil {
 			req := computing.RevokeSecurityGroupIngressInput{
 				GroupName: nifcloud.String(sgID),
@@ -244,6 +232,18 @@ func resourceNifcloudSecurityGroupRuleUpdate(d *schema.ResourceData, meta interf
 					d.Id(), err)
 			}
 		}
+	} else if err := resumeSecurityGroupRules(conn, sgID, expandIPPerm(meta, d.Get("rules")), timeout); err != nil {
+		return fmt.Errorf(
+			"Error authorizing rule for Security Group (%s): %s",
+			d.Id(), err)
+	}
+
+	statuses, err := waitForSecurityGroupRulesApplied(conn, sgID, expandIPPerm(meta, d.Get("rules")), timeout)
+	if statuses != nil {
+		d.Set("rule_status", statuses)
+	}
+	if err != nil {
+		return fmt.Errorf("Error waiting for Security Group Rule (%s) to be applied: %s", d.Id(), err)
 	}
 
 	return resourceNifcloudSecurityGroupRuleRead(d, meta)
@@ -254,39 +254,39 @@ func resourceNifcloudSecurityGroupRuleRead(d *schema.ResourceData, meta interfac
 	sgID := d.Get("name").(string)
 	sg, err := findResourceSecurityGroup(conn, sgID)
 	if err != nil {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroup") {
+			log.Printf("[WARN] Security Group (%s) not found, removing Security Group Rule (%s) from state", sgID, d.Id())
+			d.SetId("")
+			return nil
+		}
 		return fmt.Errorf("Error finding security group (%s) for rule (%s): %s", sgID, d.Id(), err)
 	}
 
-	var rule *computing.IpPermissionsSetItem
-	var rules []*computing.IpPermissionsSetItem
-	rules = sg.IpPermissions
-//	log.Printf("[DEBUG] Rules %v", rules)
-
-	perm := expandIPPerm(d.Get("rules"))
+	perm := expandIPPerm(meta, d.Get("rules"))
 	if perm == nil {
 		return err
 	}
 
-	if len(rules) == 0 {
-		log.Printf("[WARN] No rules were found for Security Group (%s) looking for Security Group Rule (%s)",
-			sgID, d.Id())
-		d.SetId("")
-		return nil
-	}
+	rule := findRuleMatch(perm[0], sg.IpPermissions)
 
-	rule = findRuleMatch(perm[0], rules)
-
-	if rule == nil {
+	if rule == nil && securityGroupApplied(sg) {
 		log.Printf("[DEBUG] Unable to find matching Security Group Rule (%s) for Group %s",
 			d.Id(), sgID)
 		d.SetId("")
 		return nil
 	}
 
-	log.Printf("[DEBUG] Found rule for Security Group Rule (%s): %s", d.Id(), rule)
+	if rule == nil {
+		log.Printf("[DEBUG] Security Group Rule (%s) for Group %s is not applied yet", d.Id(), sgID)
+	} else {
+		log.Printf("[DEBUG] Found rule for Security Group Rule (%s): %s", d.Id(), rule)
+	}
 
 	d.Set("name", sg.GroupName)
 	d.Set("rules", d.Get("rules"))
+	if err := d.Set("rule_status", securityGroupRuleStatuses(sg, perm)); err != nil {
+		return fmt.Errorf("Error setting rule_status: %s", err)
+	}
 
 	if strings.Contains(d.Id(), "_") {
 		// import so fix the id
@@ -304,8 +304,7 @@ func findResourceSecurityGroup(conn *computing.Computing, id string) (*computing
 
 	out, err := conn.DescribeSecurityGroups(&input)
 	if err != nil {
-		awsErr, ok := err.(awserr.Error)
-		if ok && awsErr.Code() == "Client.InvalidParameterNotFound.SecurityGroup" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroup") {
 			return nil, err
 		}
 		return nil, fmt.Errorf("Couldn't find SecurityGroup resource: %s", err)
@@ -320,7 +319,7 @@ func findResourceSecurityGroup(conn *computing.Computing, id string) (*computing
 	}
 
 	resp, err := conn.DescribeSecurityGroups(&req)
-	if err, ok := err.(awserr.Error); ok && err.Code() == "Client.InvalidParameterNotFound.SecurityGroup" {
+	if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.SecurityGroup") {
 		return nil, securityGroupNotFound{id, nil}
 	}
 	if err != nil {
@@ -369,6 +368,10 @@ func findRuleMatch(p *computing.RequestIpPermissionsStruct, rules []*computing.I
 			continue
 		}
 
+		if p.InOut != nil && r.InOut != nil && *p.InOut != *r.InOut {
+			continue
+		}
+
 		remaining := len(p.RequestIpRanges)
 		for _, ip := range p.RequestIpRanges {
 			for _, rip := range r.IpRanges {
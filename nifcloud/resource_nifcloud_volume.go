@@ -22,6 +22,12 @@ func resourceNifcloudVolume() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
@@ -66,12 +72,17 @@ func resourceNifcloudVolumeCreate(d *schema.ResourceData, meta interface{}) erro
 		DiskType:       nifcloud.String(d.Get("disk_type").(string)),
 		InstanceId:     nifcloud.String(d.Get("instance_id").(string)),
 		AccountingType: nifcloud.String(d.Get("accounting_type").(string)),
-		Description:    nifcloud.String(d.Get("description").(string)),
+		Description:    withDescriptionPrefix(meta, d.Get("description").(string)),
 	}
 
 	log.Printf(
 		"[DEBUG] EBS Volume create opts: %v", request)
-	result, err := conn.CreateVolume(&request)
+	var result *computing.CreateVolumeOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		result, err = conn.CreateVolume(&request)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error creating EC2 volume: %s", err)
 	}
@@ -82,7 +93,7 @@ func resourceNifcloudVolumeCreate(d *schema.ResourceData, meta interface{}) erro
 		Pending:    []string{"creating"},
 		Target:     []string{"available", "in-use"},
 		Refresh:    volumeStateRefreshFunc(conn, *result.VolumeId),
-		Timeout:    5 * time.Minute,
+		Timeout:    d.Timeout(schema.TimeoutCreate),
 		Delay:      10 * time.Second,
 		MinTimeout: 3 * time.Second,
 	}
@@ -106,7 +117,7 @@ func resourceNifcloudVolumeUpdate(d *schema.ResourceData, meta interface{}) erro
 		_, err := conn.ModifyVolumeAttribute(&computing.ModifyVolumeAttributeInput{
 			VolumeId:  nifcloud.String(d.Id()),
 			Attribute: nifcloud.String("description"),
-			Value:     nifcloud.String(d.Get("description").(string)),
+			Value:     withDescriptionPrefix(meta, d.Get("description").(string)),
 		})
 		if err != nil {
 			return err
@@ -116,7 +127,7 @@ func resourceNifcloudVolumeUpdate(d *schema.ResourceData, meta interface{}) erro
 			Pending:    []string{"creating", "configuring"},
 			Target:     []string{"available", "in-use"},
 			Refresh:    volumeStateRefreshFunc(conn, d.Id()),
-			Timeout:    5 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -145,7 +156,7 @@ func resourceNifcloudVolumeUpdate(d *schema.ResourceData, meta interface{}) erro
 			Pending:    []string{"creating", "configuring"},
 			Target:     []string{"available", "in-use"},
 			Refresh:    volumeStateRefreshFunc(conn, d.Id()),
-			Timeout:    5 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -172,7 +183,7 @@ func resourceNifcloudVolumeUpdate(d *schema.ResourceData, meta interface{}) erro
 			Pending:    []string{"creating", "configuring"},
 			Target:     []string{"available", "in-use"},
 			Refresh:    volumeStateRefreshFunc(conn, d.Id()),
-			Timeout:    5 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -222,7 +233,7 @@ func resourceNifcloudVolumeRead(d *schema.ResourceData, meta interface{}) error
 
 	response, err := conn.DescribeVolumes(&request)
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.Volume" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Volume") {
 			d.SetId("")
 			return nil
 		}
@@ -230,7 +241,9 @@ func resourceNifcloudVolumeRead(d *schema.ResourceData, meta interface{}) error
 	}
 
 	if response == nil || len(response.VolumeSet) == 0 || response.VolumeSet[0] == nil {
-		return fmt.Errorf("error reading EC2 Volume (%s): empty response", d.Id())
+		log.Printf("[WARN] Volume (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
 	}
 
 	volume := response.VolumeSet[0]
@@ -240,6 +253,7 @@ func resourceNifcloudVolumeRead(d *schema.ResourceData, meta interface{}) error
 	d.Set("disk_type", voDiskTypes()[nifcloud.StringValue(volume.DiskType)])
 	//d.Set("accounting_type", volume.AccountingType)
 	d.Set("accounting_type", volume.NextMonthAccountingType)
+	d.Set("description", trimDescriptionPrefix(meta, volume.Description))
 
 	return nil
 }
@@ -254,7 +268,7 @@ func resourceNifcloudVolumeDelete(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	if _, err := conn.DetachVolume(&detach); err != nil {
-		if !isNifcloudErr(err, "Client.Inoperable.Volume.DetachedFromInstance",""){
+		if !isNifcloudErr(err, "Client.Inoperable.Volume.DetachedFromInstance", "") {
 			return fmt.Errorf("Error DetachVolumeInput: %s", err)
 		}
 	}
@@ -263,10 +277,10 @@ func resourceNifcloudVolumeDelete(d *schema.ResourceData, meta interface{}) erro
 		VolumeId: nifcloud.String(d.Id()),
 	}
 
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, err := conn.DeleteVolume(&input)
 
-		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.Volume" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Volume") {
 			return nil
 		}
 
@@ -290,7 +304,7 @@ func resourceNifcloudVolumeDelete(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	var output *computing.DescribeVolumesOutput
-	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		var err error
 		output, err = conn.DescribeVolumes(&describeInput)
 
@@ -317,7 +331,7 @@ func resourceNifcloudVolumeDelete(d *schema.ResourceData, meta interface{}) erro
 		output, err = conn.DescribeVolumes(&describeInput)
 	}
 
-	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.Volume" {
+	if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.Volume") {
 		return nil
 	}
 
@@ -3,10 +3,10 @@ package nifcloud
 import (
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -23,6 +23,18 @@ func resourceNifcloudVpnConnection() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(40 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		CustomizeDiff: resourceNifcloudVpnConnectionCustomizeDiff,
+
+		SchemaVersion: 1,
+		MigrateState:  resourceNifcloudVpnConnectionMigrateState,
+
+		// VPN connection can't be modified at all, so every argument is ForceNew.
+		// What the API fills in is kept in the computed attributes below.
 		Schema: map[string]*schema.Schema{
 			"vpn_gateway_id": {
 				Type:     schema.TypeString,
@@ -50,62 +62,55 @@ func resourceNifcloudVpnConnection() *schema.Resource {
 
 			// IPSec
 			"ipsec": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Required: true,
-//				Optional: true,
 				ForceNew: true,
-				MinItems: 0,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"dh": {
-							Type:     schema.TypeInt,
-							Default:  2,
-//							Required: true,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeInt,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"esp_life_time": {
-							Type:     schema.TypeInt,
-							Default:  3600,
-//							Required: true,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeInt,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"ike_life_time": {
-							Type:     schema.TypeInt,
-							Default:  28800,
-//							Required: true,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeInt,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"encryption_algorithm": {
-							Type:     schema.TypeString,
-							Default:  "AES128",
-//							Required: true,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"hash_algorithm": {
-							Type:     schema.TypeString,
-							Default:  "SHA1",
-//							Required: true,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"ike_version": {
-							Type:     schema.TypeString,
-							Default:  "IKEv1",
-//							Required: true,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"pre_shared_key": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							Computed:     true,
-							ValidateFunc: validateVpnConnectionTunnelPreSharedKey,
-//							ForceNew:     true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							ForceNew:         true,
+							ValidateFunc:     validateVpnConnectionTunnelPreSharedKey,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 					},
 				},
@@ -113,73 +118,200 @@ func resourceNifcloudVpnConnection() *schema.Resource {
 
 			// "L2TPv3 / IPSec" Only
 			"tunnel": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Optional: true,
 				ForceNew: true,
-				MinItems: 0,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"type": {
-							Type:     schema.TypeString,
-							Default:  "L2TPv3",
-//							Required: true,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"mode": {
 							Type:     schema.TypeString,
 							Required: true,
-//							ForceNew: true,
+							ForceNew: true,
 						},
 						"encapsulation": {
 							Type:     schema.TypeString,
 							Required: true,
-//							ForceNew: true,
+							ForceNew: true,
 						},
 						"mtu": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "1500",
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"peer_session_id": {
-							Type:     schema.TypeString,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"peer_tunnel_id": {
-							Type:     schema.TypeString,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"session_id": {
-							Type:     schema.TypeString,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"tunnel_id": {
-							Type:     schema.TypeString,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"destination_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 						"source_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-//							ForceNew: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressVpnConnectionDefault,
 						},
 					},
 				},
 			},
+
+			"pre_shared_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"ipsec_configuration": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dh":                   {Type: schema.TypeInt, Computed: true},
+						"esp_life_time":        {Type: schema.TypeInt, Computed: true},
+						"ike_life_time":        {Type: schema.TypeInt, Computed: true},
+						"encryption_algorithm": {Type: schema.TypeString, Computed: true},
+						"hash_algorithm":       {Type: schema.TypeString, Computed: true},
+						"ike_version":          {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+
+			// Customer side device configuration per vendor: cisco_ios, yamaha_rtx, strongswan
+			"customer_gateway_configuration": {
+				Type:      schema.TypeMap,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+			},
+
+			"tunnel_configuration": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type":             {Type: schema.TypeString, Computed: true},
+						"mode":             {Type: schema.TypeString, Computed: true},
+						"encapsulation":    {Type: schema.TypeString, Computed: true},
+						"mtu":              {Type: schema.TypeString, Computed: true},
+						"peer_session_id":  {Type: schema.TypeString, Computed: true},
+						"peer_tunnel_id":   {Type: schema.TypeString, Computed: true},
+						"session_id":       {Type: schema.TypeString, Computed: true},
+						"tunnel_id":        {Type: schema.TypeString, Computed: true},
+						"destination_port": {Type: schema.TypeString, Computed: true},
+						"source_port":      {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
 		},
 	}
 }
 
+// Values the API uses when an ipsec/tunnel argument is omitted
+var vpnConnectionDefaults = map[string]interface{}{
+	"dh":                   2,
+	"esp_life_time":        3600,
+	"ike_life_time":        28800,
+	"encryption_algorithm": "AES128",
+	"hash_algorithm":       "SHA1",
+	"ike_version":          "IKEv1",
+	"type":                 "L2TPv3",
+	"mtu":                  "1500",
+}
+
+// suppressVpnConnectionDefault hides the diff of an omitted argument
+// when the state holds its default or a value generated by the API.
+func suppressVpnConnectionDefault(k, old, new string, d *schema.ResourceData) bool {
+	if new != "" && new != "0" {
+		return false
+	}
+
+	field := k[strings.LastIndex(k, ".")+1:]
+	if v, ok := vpnConnectionDefaults[field]; ok {
+		return old == fmt.Sprintf("%v", v)
+	}
+
+	// pre_shared_key and the L2TPv3 IDs/ports are generated when omitted
+	return true
+}
+
+// withVpnConnectionDefaults returns a copy of the ipsec/tunnel block with omitted arguments filled in
+func withVpnConnectionDefaults(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+		if def, ok := vpnConnectionDefaults[k]; ok && (v == "" || v == 0) {
+			result[k] = def
+		}
+	}
+	return result
+}
+
+func resourceNifcloudVpnConnectionCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	// Nothing changes in place, the computed values only have to be planned for a new connection
+	if diff.Id() != "" {
+		return nil
+	}
+
+	if ipsecs := diff.Get("ipsec").([]interface{}); len(ipsecs) > 0 && ipsecs[0] != nil {
+		ipsec := withVpnConnectionDefaults(ipsecs[0].(map[string]interface{}))
+		if psk := ipsec["pre_shared_key"].(string); psk != "" {
+			if err := diff.SetNew("pre_shared_key", psk); err != nil {
+				return err
+			}
+		}
+		delete(ipsec, "pre_shared_key")
+		if err := diff.SetNew("ipsec_configuration", []interface{}{ipsec}); err != nil {
+			return err
+		}
+	}
+
+	if tunnels := diff.Get("tunnel").([]interface{}); len(tunnels) > 0 && tunnels[0] != nil {
+		tunnel := withVpnConnectionDefaults(tunnels[0].(map[string]interface{}))
+		for _, k := range []string{"peer_session_id", "peer_tunnel_id", "session_id", "tunnel_id", "destination_port", "source_port"} {
+			if tunnel[k] == "" {
+				// Generated by the API, known after apply
+				return nil
+			}
+		}
+		if err := diff.SetNew("tunnel_configuration", []interface{}{tunnel}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func resourceNifcloudVpnConnectionCreate(d *schema.ResourceData, meta interface{}) error {
 	conn := meta.(*NifcloudClient).computingconn
 
@@ -191,7 +323,7 @@ func resourceNifcloudVpnConnectionCreate(d *schema.ResourceData, meta interface{
 	createOpts := &computing.CreateVpnConnectionInput{
 		Agreement:                     nifcloud.Bool(true),
 		CustomerGatewayId:             nifcloud.String(d.Get("customer_gateway_id").(string)),
-		NiftyVpnConnectionDescription: nifcloud.String(d.Get("description").(string)),
+		NiftyVpnConnectionDescription: withDescriptionPrefix(meta, d.Get("description").(string)),
 		VpnGatewayId:                  nifcloud.String(d.Get("vpn_gateway_id").(string)),
 		Type:                          nifcloud.String(d.Get("type").(string)),
 //		NiftyVpnConnectionMtu:         nifcloud.String(d.Get("mtu").(string)),
@@ -199,8 +331,9 @@ func resourceNifcloudVpnConnectionCreate(d *schema.ResourceData, meta interface{
 
 	if tunnels, ok := d.GetOk("tunnel"); ok {
 		tunnel := &computing.RequestNiftyTunnelStruct{}
-		for _, tmp := range tunnels.(*schema.Set).List() {
+		for _, tmp := range tunnels.([]interface{}) {
 			if v, ok := tmp.(map[string]interface{}); ok {
+				v = withVpnConnectionDefaults(v)
 				createOpts.SetNiftyVpnConnectionMtu(v["mtu"].(string))
 				tunnel.SetEncapsulation(v["encapsulation"].(string))
 				tunnel.SetMode(v["mode"].(string))
@@ -229,8 +362,9 @@ func resourceNifcloudVpnConnectionCreate(d *schema.ResourceData, meta interface{
 
 	} else if ipsecs, ok := d.GetOk("ipsec"); ok {
 		ipsec := &computing.RequestNiftyIpsecConfigurationStruct{}
-		for _, tmp := range ipsecs.(*schema.Set).List() {
+		for _, tmp := range ipsecs.([]interface{}) {
 			if v, ok := tmp.(map[string]interface{}); ok {
+				v = withVpnConnectionDefaults(v)
 				ipsec.SetDiffieHellmanGroup(int64(v["dh"].(int)))
 				ipsec.SetEncapsulatingSecurityPayloadLifetime(int64(v["esp_life_time"].(int)))
 				ipsec.SetEncryptionAlgorithm(v["encryption_algorithm"].(string))
@@ -251,15 +385,19 @@ func resourceNifcloudVpnConnectionCreate(d *schema.ResourceData, meta interface{
 
 	// Create the VPN Connection
 	log.Printf("[DEBUG] Creating vpn connection")
-	resp, err := conn.CreateVpnConnection(createOpts)
+	var resp *computing.CreateVpnConnectionOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		resp, err = conn.CreateVpnConnection(createOpts)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("Error creating vpn connection: %s", err)
 	}
 
 	d.SetId(nifcloud.StringValue(resp.VpnConnection.VpnConnectionId))
-	log.Printf("[DEBUG] vpn connection create %v", resp)
 
-	if err := waitForEc2VpnConnectionAvailable(conn, d.Id()); err != nil {
+	if err := waitForEc2VpnConnectionAvailable(conn, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
 		return fmt.Errorf("error waiting for VPN connection (%s) to become available: %s", d.Id(), err)
 	}
 
@@ -274,7 +412,7 @@ func vpnConnectionRefreshFunc(conn *computing.Computing, connectionID string) re
 		})
 
 		if err != nil {
-			if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.VpnConnectionId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnConnectionId") {
 				resp = nil
 			} else {
 				log.Printf("Error on VPNConnectionRefresh: %s", err)
@@ -298,7 +436,7 @@ func resourceNifcloudVpnConnectionRead(d *schema.ResourceData, meta interface{})
 		VpnConnectionId: []*string{nifcloud.String(d.Id())},
 	})
 
-	if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.VpnConnectionId" {
+	if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnConnectionId") {
 		log.Printf("[WARN] EC2 VPN Connection (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -309,37 +447,75 @@ func resourceNifcloudVpnConnectionRead(d *schema.ResourceData, meta interface{})
 	}
 
 	if resp == nil || len(resp.VpnConnectionSet) == 0 || resp.VpnConnectionSet[0] == nil {
-		return fmt.Errorf("error reading EC2 VPN Connection (%s): empty response", d.Id())
+		log.Printf("[WARN] EC2 VPN Connection (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
 	}
 
 	if len(resp.VpnConnectionSet) > 1 {
 		return fmt.Errorf("error reading EC2 VPN Connection (%s): multiple responses", d.Id())
 	}
 
-	log.Printf("[DEBUG] vpn connection describe %v", resp)
 	vpnConnection := resp.VpnConnectionSet[0]
 
 	// Set attributes under the user's control.
 	d.Set("vpn_gateway_id", vpnConnection.VpnGatewayId)
 	d.Set("customer_gateway_id", vpnConnection.CustomerGatewayId)
 	d.Set("type", vpnConnection.Type)
-	d.Set("description", vpnConnection.NiftyVpnConnectionDescription)
+	d.Set("description", trimDescriptionPrefix(meta, vpnConnection.NiftyVpnConnectionDescription))
 
+	// What the API returns goes to the computed attributes, ipsec/tunnel keep the configuration as written.
+	// They are only filled from the API after an import.
 	if vpnConnection.NiftyIpsecConfiguration != nil {
 		ipsec := vpnConnection.NiftyIpsecConfiguration
-		d.Set("mtu", ipsec.Mtu)
-		if err := d.Set("ipsec", ipsecToMapList(ipsec)); err != nil {
+		d.Set("pre_shared_key", ipsec.PreSharedKey)
+
+		ipsecConfiguration := ipsecToMapList(ipsec)
+		if len(d.Get("ipsec").([]interface{})) == 0 {
+			if err := d.Set("ipsec", ipsecConfiguration); err != nil {
+				return err
+			}
+		}
+		for _, m := range ipsecConfiguration {
+			delete(m, "pre_shared_key")
+		}
+		if err := d.Set("ipsec_configuration", ipsecConfiguration); err != nil {
 			return err
 		}
 	}
 
 	if vpnConnection.NiftyTunnel != nil {
-		tunnel := vpnConnection.NiftyTunnel
-		if err := d.Set("tunnel", tunnelToMapList(tunnel)); err != nil {
+		tunnelConfiguration := tunnelToMapList(vpnConnection.NiftyTunnel)
+		for _, m := range tunnelConfiguration {
+			if vpnConnection.NiftyIpsecConfiguration != nil {
+				m["mtu"] = nifcloud.StringValue(vpnConnection.NiftyIpsecConfiguration.Mtu)
+			}
+		}
+		if len(d.Get("tunnel").([]interface{})) == 0 {
+			if err := d.Set("tunnel", tunnelConfiguration); err != nil {
+				return err
+			}
+		}
+		if err := d.Set("tunnel_configuration", tunnelConfiguration); err != nil {
 			return err
 		}
 	}
 
+	// The device configuration also depends on the gateways (addresses, LAN
+	// side CIDR), which can change without touching the connection, so it is
+	// rendered again on every refresh.
+	configuration, err := newVpnConnectionConfiguration(conn, vpnConnection)
+	if err != nil {
+		return err
+	}
+	rendered, err := configuration.render()
+	if err != nil {
+		return err
+	}
+	if err := d.Set("customer_gateway_configuration", rendered); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -369,12 +545,11 @@ func resourceNifcloudVpnConnectionDelete(d *schema.ResourceData, meta interface{
 	request := &computing.DeleteVpnConnectionInput{
 		VpnConnectionId: nifcloud.String(d.Id()),
 	}
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
-		resp, err := conn.DeleteVpnConnection(request)
-		log.Printf("[DEBUG] deleting vpn connection %v", resp)
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := conn.DeleteVpnConnection(request)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.VpnConnectionId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnConnectionId") {
 				return nil
 			}
 			return resource.RetryableError(err)
@@ -387,7 +562,7 @@ func resourceNifcloudVpnConnectionDelete(d *schema.ResourceData, meta interface{
 		VpnConnectionId: nifcloud.String(d.Id()),
 	})
 
-	if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.VpnConnectionId" {
+	if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnConnectionId") {
 		return nil
 	}
 */
@@ -398,7 +573,7 @@ func resourceNifcloudVpnConnectionDelete(d *schema.ResourceData, meta interface{
 	return nil
 }
 
-func waitForEc2VpnConnectionAvailable(conn *computing.Computing, id string) error {
+func waitForEc2VpnConnectionAvailable(conn *computing.Computing, id string, timeout time.Duration) error {
 	// Wait for the connection to become available. This has an obscenely
 	// high default timeout because AWS VPN connections are notoriously
 	// slow at coming up or going down. There's also no point in checking
@@ -407,7 +582,7 @@ func waitForEc2VpnConnectionAvailable(conn *computing.Computing, id string) erro
 		Pending:    []string{"pending"},
 		Target:     []string{"available"},
 		Refresh:    vpnConnectionRefreshFunc(conn, id),
-		Timeout:    40 * time.Minute,
+		Timeout:    timeout,
 		Delay:      10 * time.Second,
 		MinTimeout: 10 * time.Second,
 	}
@@ -0,0 +1,44 @@
+package nifcloud
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func resourceNifcloudVpnConnectionMigrateState(
+	v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		log.Println("[INFO] Found NIFCLOUD VPN Connection State v0; migrating to v1")
+		return migrateVpnConnectionStateV0toV1(is)
+	default:
+		return is, fmt.Errorf("Unexpected schema version: %d", v)
+	}
+}
+
+func migrateVpnConnectionStateV0toV1(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is.Empty() {
+		log.Println("[DEBUG] Empty InstanceState; nothing to migrate.")
+		return is, nil
+	}
+
+	// ipsec and tunnel changed from TypeSet to TypeList, both hold a single element
+	// so ipsec.<hash>.dh becomes ipsec.0.dh. Only the keys are logged, the values
+	// include the pre-shared key.
+	renamed := make(map[string]string)
+	for k, v := range is.Attributes {
+		parts := strings.SplitN(k, ".", 3)
+		if len(parts) != 3 || (parts[0] != "ipsec" && parts[0] != "tunnel") || parts[1] == "0" {
+			continue
+		}
+		delete(is.Attributes, k)
+		is.Attributes[parts[0]+".0."+parts[2]] = v
+		renamed[k] = parts[0] + ".0." + parts[2]
+	}
+
+	log.Printf("[DEBUG] Attributes renamed by the migration: %v", renamed)
+	return is, nil
+}
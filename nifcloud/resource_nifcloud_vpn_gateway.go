@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/shztki/nifcloud-sdk-go/nifcloud"
-	"github.com/shztki/nifcloud-sdk-go/nifcloud/awserr"
 	"github.com/shztki/nifcloud-sdk-go/service/computing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -24,6 +23,12 @@ func resourceNifcloudVpnGateway() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"availability_zone": {
 				Type:     schema.TypeString,
@@ -91,7 +96,7 @@ func resourceNifcloudVpnGatewayCreate(d *schema.ResourceData, meta interface{})
 	createOpts := &computing.CreateVpnGatewayInput{
 		NiftyVpnGatewayName:        nifcloud.String(d.Get("name").(string)),
 		NiftyNetwork:               niftyNetwork,
-		NiftyVpnGatewayDescription: nifcloud.String(d.Get("description").(string)),
+		NiftyVpnGatewayDescription: withDescriptionPrefix(meta, d.Get("description").(string)),
 		NiftyVpnGatewayType:        nifcloud.String(d.Get("vpn_gateway_type").(string)),
 		AccountingType:             nifcloud.String(d.Get("accounting_type").(string)),
 		SecurityGroup:              securityGroups,
@@ -100,7 +105,12 @@ func resourceNifcloudVpnGatewayCreate(d *schema.ResourceData, meta interface{})
 
 	// Create the Vpn Gateway.
 	log.Printf("[DEBUG] Creating vpn gateway")
-	resp, err := conn.CreateVpnGateway(createOpts)
+	var resp *computing.CreateVpnGatewayOutput
+	err := retryOnNifcloudTransientErr(d.Timeout(schema.TimeoutCreate), func() error {
+		var err error
+		resp, err = conn.CreateVpnGateway(createOpts)
+		return err
+	})
 //	var resp *computing.CreateVpnGatewayOutput
 //	err := resource.Retry(20*time.Minute, func() *resource.RetryError {
 //		var err error
@@ -137,7 +147,7 @@ func resourceNifcloudVpnGatewayCreate(d *schema.ResourceData, meta interface{})
 		Pending:    []string{"pending", "warning"},
 		Target:     []string{"available"},
 		Refresh:    vpnGatewayRefreshFunc(conn, *vpnGateway.VpnGatewayId),
-		Timeout:    15 * time.Minute,
+		Timeout:    d.Timeout(schema.TimeoutCreate),
 		Delay:      10 * time.Second,
 		MinTimeout: 3 * time.Second,
 	}
@@ -163,7 +173,7 @@ func vpnGatewayRefreshFunc(conn *computing.Computing, gatewayID string) resource
 			Filter: []*computing.RequestFilterStruct{gatewayFilter},
 		})
 		if err != nil {
-			if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.VpnGatewayId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnGatewayId") {
 				resp = nil
 			} else {
 				log.Printf("Error on VpnGatewayRefresh: %s", err)
@@ -213,7 +223,7 @@ func resourceNifcloudVpnGatewayRead(d *schema.ResourceData, meta interface{}) er
 		Filter: []*computing.RequestFilterStruct{gatewayFilter},
 	})
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "Client.InvalidParameterNotFound.VpnGatewayId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnGatewayId") {
 			d.SetId("")
 			return nil
 		}
@@ -221,11 +231,15 @@ func resourceNifcloudVpnGatewayRead(d *schema.ResourceData, meta interface{}) er
 		return err
 	}
 
+	if len(resp.VpnGatewaySet) == 0 {
+		log.Printf("[WARN] Vpn Gateway (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if len(resp.VpnGatewaySet) != 1 {
 		return fmt.Errorf("Error finding VpnGateway: %s", d.Id())
 	}
 
-	log.Printf("[DEBUG] vpn gateway describe %v", resp)
 	if *resp.VpnGatewaySet[0].State == "deleted" {
 		log.Printf("[INFO] Vpn Gateway is in `deleted` state: %s", d.Id())
 		d.SetId("")
@@ -235,7 +249,7 @@ func resourceNifcloudVpnGatewayRead(d *schema.ResourceData, meta interface{}) er
 	vpnGateway := resp.VpnGatewaySet[0]
 	d.Set("name", vpnGateway.NiftyVpnGatewayName)
 	d.Set("ip_address", vpnGateway.IpAddress)
-	d.Set("description", vpnGateway.NiftyVpnGatewayDescription)
+	d.Set("description", trimDescriptionPrefix(meta, vpnGateway.NiftyVpnGatewayDescription))
 	d.Set("vpn_gateway_type", vpnGateway.NiftyVpnGatewayType)
 	d.Set("accounting_type", vpnGateway.NextMonthAccountingType)
 	d.Set("availability_zone", vpnGateway.AvailabilityZone)
@@ -263,7 +277,7 @@ func resourceNifcloudVpnGatewayUpdate(d *schema.ResourceData, meta interface{})
 		input := computing.NiftyModifyVpnGatewayAttributeInput{
 			VpnGatewayId: nifcloud.String(d.Id()),
 			Attribute:    nifcloud.String("niftyVpnGatewayDescription"),
-			Value:        nifcloud.String(d.Get("description").(string)),
+			Value:        withDescriptionPrefix(meta, d.Get("description").(string)),
 			Agreement:    nifcloud.Bool(false),
 		}
 		_, err := conn.NiftyModifyVpnGatewayAttribute(&input)
@@ -288,7 +302,7 @@ func resourceNifcloudVpnGatewayUpdate(d *schema.ResourceData, meta interface{})
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    vpnGatewayRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -317,7 +331,7 @@ func resourceNifcloudVpnGatewayUpdate(d *schema.ResourceData, meta interface{})
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    vpnGatewayRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -346,7 +360,7 @@ func resourceNifcloudVpnGatewayUpdate(d *schema.ResourceData, meta interface{})
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    vpnGatewayRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -380,7 +394,7 @@ func resourceNifcloudVpnGatewayUpdate(d *schema.ResourceData, meta interface{})
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    vpnGatewayRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -409,7 +423,7 @@ func resourceNifcloudVpnGatewayUpdate(d *schema.ResourceData, meta interface{})
 			Pending:    []string{"pending", "warning"},
 			Target:     []string{"available"},
 			Refresh:    vpnGatewayRefreshFunc(conn, d.Id()),
-			Timeout:    15 * time.Minute,
+			Timeout:    d.Timeout(schema.TimeoutUpdate),
 			Delay:      10 * time.Second,
 			MinTimeout: 3 * time.Second,
 		}
@@ -433,12 +447,11 @@ func resourceNifcloudVpnGatewayDelete(d *schema.ResourceData, meta interface{})
 	request := &computing.DeleteVpnGatewayInput{
 		VpnGatewayId: nifcloud.String(d.Id()),
 	}
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
-		resp, err := conn.DeleteVpnGateway(request)
-		log.Printf("[DEBUG] deleting vpn gateway %v", resp)
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := conn.DeleteVpnGateway(request)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.VpnGatewayId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnGatewayId") {
 				return nil
 			}
 			return resource.RetryableError(err)
@@ -451,7 +464,7 @@ func resourceNifcloudVpnGatewayDelete(d *schema.ResourceData, meta interface{})
 		VpnGatewayId: nifcloud.String(d.Id()),
 	})
 	if err != nil {
-		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.VpnGatewayId" {
+		if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnGatewayId") {
 			return nil
 		}
 		return fmt.Errorf("[ERROR] Error deleting VpnGateway: %s", err)
@@ -465,12 +478,11 @@ func resourceNifcloudVpnGatewayDelete(d *schema.ResourceData, meta interface{})
 	input := &computing.DescribeVpnGatewaysInput{
 		Filter: []*computing.RequestFilterStruct{gatewayFilter},
 	}
-	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		resp, err := conn.DescribeVpnGateways(input)
-		log.Printf("[DEBUG] delete after describe 001 %v", resp)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "Client.InvalidParameterNotFound.VpnGatewayId" {
+			if isNifcloudNotFoundErr(err, "Client.InvalidParameterNotFound.VpnGatewayId") {
 				return nil
 			}
 			return resource.RetryableError(err)
@@ -486,7 +498,6 @@ func resourceNifcloudVpnGatewayDelete(d *schema.ResourceData, meta interface{})
 	if isResourceTimeoutError(err) {
 		var resp *computing.DescribeVpnGatewaysOutput
 		resp, err = conn.DescribeVpnGateways(input)
-		log.Printf("[DEBUG] delete after describe 002 %v", resp)
 
 		if err != nil {
 			return checkVpnGatewayDeleteResponse(resp, d.Id())
@@ -0,0 +1,242 @@
+package nifcloud
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+)
+
+// AuthorizeSecurityGroupIngress only queues the rules: the group stays
+// "processing" and the rules show up in DescribeSecurityGroups one by one,
+// sometimes long after the call returned.
+const (
+	securityGroupStatusApplied = "applied"
+
+	securityGroupRuleStatusApplied = "applied"
+	securityGroupRuleStatusPending = "pending"
+)
+
+func securityGroupRuleStatusSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rule_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"status": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+// securityGroupApplied is true when the group has no rule changes in progress,
+// older responses without a status are taken as applied.
+func securityGroupApplied(group *computing.SecurityGroupInfoSetItem) bool {
+	return group.GroupStatus == nil || nifcloud.StringValue(group.GroupStatus) == securityGroupStatusApplied
+}
+
+// securityGroupRuleStatuses reports each rule as applied once it is listed
+// in the group and the group itself is no longer processing.
+func securityGroupRuleStatuses(group *computing.SecurityGroupInfoSetItem, perms []*computing.RequestIpPermissionsStruct) []map[string]interface{} {
+	groupApplied := securityGroupApplied(group)
+
+	statuses := make([]map[string]interface{}, 0, len(perms))
+	for _, perm := range perms {
+		status := securityGroupRuleStatusPending
+		if groupApplied && findRuleMatch(perm, group.IpPermissions) != nil {
+			status = securityGroupRuleStatusApplied
+		}
+		statuses = append(statuses, map[string]interface{}{
+			"rule_id": ipPermissionIDHash(nifcloud.StringValue(group.GroupName), perm),
+			"status":  status,
+		})
+	}
+
+	return statuses
+}
+
+// missingSecurityGroupRules returns the perms the group doesn't list yet.
+func missingSecurityGroupRules(group *computing.SecurityGroupInfoSetItem, perms []*computing.RequestIpPermissionsStruct) []*computing.RequestIpPermissionsStruct {
+	var missing []*computing.RequestIpPermissionsStruct
+	for _, perm := range perms {
+		if findRuleMatch(perm, group.IpPermissions) == nil {
+			missing = append(missing, perm)
+		}
+	}
+
+	return missing
+}
+
+// resumeSecurityGroupRules authorizes again the rules an earlier apply left
+// pending that never showed up. While the group is still processing they may
+// be queued, so nothing is sent until it is applied.
+func resumeSecurityGroupRules(conn *computing.Computing, groupName string, perms []*computing.RequestIpPermissionsStruct, timeout time.Duration) error {
+	group, err := findResourceSecurityGroup(conn, groupName)
+	if err != nil {
+		return err
+	}
+	if !securityGroupApplied(group) {
+		return nil
+	}
+
+	missing := missingSecurityGroupRules(group, perms)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Authorizing %d missing rules for Security Group (%s)", len(missing), groupName)
+	return retryOnNifcloudTransientErr(timeout, func() error {
+		_, err := conn.AuthorizeSecurityGroupIngress(&computing.AuthorizeSecurityGroupIngressInput{
+			GroupName:     nifcloud.String(groupName),
+			IpPermissions: missing,
+		})
+		return err
+	})
+}
+
+// authorizeSecurityGroupIngress retries while the group is still processing
+// an earlier change, e.g. the revoke of the rule being replaced.
+func authorizeSecurityGroupIngress(conn *computing.Computing, req *computing.AuthorizeSecurityGroupIngressInput, timeout time.Duration) error {
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		_, err := conn.AuthorizeSecurityGroupIngress(req)
+		if err != nil {
+			if isNifcloudProcessingErr(err) || isNifcloudTransientErr(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if isResourceTimeoutError(err) {
+		_, err = conn.AuthorizeSecurityGroupIngress(req)
+	}
+	return err
+}
+
+// waitForSecurityGroupApplied waits for the group to finish its pending rule changes.
+func waitForSecurityGroupApplied(conn *computing.Computing, groupName string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Waiting for Security Group (%s) to be applied", groupName)
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{securityGroupRuleStatusPending},
+		Target:  []string{securityGroupStatusApplied},
+		Refresh: func() (interface{}, string, error) {
+			group, err := findResourceSecurityGroup(conn, groupName)
+			if err != nil {
+				return nil, "", err
+			}
+			if !securityGroupApplied(group) {
+				return group, securityGroupRuleStatusPending, nil
+			}
+			return group, securityGroupStatusApplied, nil
+		},
+		Timeout:    timeout,
+		Delay:      5 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+func pendingSecurityGroupRules(statuses []map[string]interface{}) []string {
+	var pending []string
+	for _, s := range statuses {
+		if s["status"].(string) != securityGroupRuleStatusApplied {
+			pending = append(pending, s["rule_id"].(string))
+		}
+	}
+
+	return pending
+}
+
+// SecurityGroupRulesStateRefreshFunc returns the rule statuses of the group
+// and "applied" once none of them is pending.
+func SecurityGroupRulesStateRefreshFunc(conn *computing.Computing, groupName string, perms []*computing.RequestIpPermissionsStruct) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		group, err := findResourceSecurityGroup(conn, groupName)
+		if err != nil {
+			return nil, "", err
+		}
+
+		statuses := securityGroupRuleStatuses(group, perms)
+		if pending := pendingSecurityGroupRules(statuses); len(pending) > 0 {
+			log.Printf("[DEBUG] Security Group (%s) rules still pending: %s", groupName, strings.Join(pending, ", "))
+			return statuses, securityGroupRuleStatusPending, nil
+		}
+
+		return statuses, securityGroupRuleStatusApplied, nil
+	}
+}
+
+// waitForSecurityGroupRulesApplied waits for every rule in perms to be applied
+// and always returns the last statuses it saw, so callers can keep the pending
+// rules in the state and pick them up again on the next apply.
+func waitForSecurityGroupRulesApplied(conn *computing.Computing, groupName string, perms []*computing.RequestIpPermissionsStruct, timeout time.Duration) ([]map[string]interface{}, error) {
+	if len(perms) == 0 {
+		return nil, nil
+	}
+
+	log.Printf("[DEBUG] Waiting for Security Group (%s) rules to be applied", groupName)
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{securityGroupRuleStatusPending},
+		Target:     []string{securityGroupRuleStatusApplied},
+		Refresh:    SecurityGroupRulesStateRefreshFunc(conn, groupName, perms),
+		Timeout:    timeout,
+		Delay:      5 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+
+	v, err := stateConf.WaitForState()
+	if err == nil {
+		return v.([]map[string]interface{}), nil
+	}
+	if !isResourceTimeoutError(err) {
+		return nil, err
+	}
+
+	group, gerr := findResourceSecurityGroup(conn, groupName)
+	if gerr != nil {
+		return nil, err
+	}
+	statuses := securityGroupRuleStatuses(group, perms)
+	pending := pendingSecurityGroupRules(statuses)
+	if len(pending) == 0 {
+		return statuses, nil
+	}
+
+	return statuses, fmt.Errorf(
+		"Security Group (%s) rules still pending after %s: %s",
+		groupName, timeout, strings.Join(pending, ", "))
+}
+
+// securityGroupRuleStatusCustomizeDiff plans an update while a rule is still
+// pending, the update then waits for it and authorizes only what is missing.
+func securityGroupRuleStatusCustomizeDiff(diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+	if diff.HasChange("rules") {
+		return diff.SetNewComputed("rule_status")
+	}
+
+	for _, s := range diff.Get("rule_status").([]interface{}) {
+		if m, ok := s.(map[string]interface{}); ok && m["status"].(string) != securityGroupRuleStatusApplied {
+			log.Printf("[DEBUG] Security Group rule %s is still pending, planning to wait for it", m["rule_id"])
+			return diff.SetNewComputed("rule_status")
+		}
+	}
+
+	return nil
+}
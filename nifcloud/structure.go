@@ -1,6 +1,7 @@
 package nifcloud
 
 import (
+	"fmt"
 	"strings"
 	
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -102,24 +103,13 @@ func expandRequestLoadBalancerNames(name string, configured []interface{}) ([]*c
 			LoadBalancerName: nifcloud.String(name),
 		}
 
-		if ip := data["instance_port"].(int); ip > 0 {
-			l.SetInstancePort(int64(ip))
-		} else if p := data["protocol"].(string); p == "HTTP" {
-			l.SetInstancePort(int64(80))
-		} else if p := data["protocol"].(string); p == "HTTPS" {
-			l.SetInstancePort(int64(443))
-		} else if p := data["protocol"].(string); p == "FTP" {
-			l.SetInstancePort(int64(21))
+		// Ports fall back to the well-known port of the protocol
+		lbPort, instancePort := lbListenerPorts(data)
+		if instancePort > 0 {
+			l.SetInstancePort(instancePort)
 		}
-
-		if lp := data["lb_port"].(int); lp > 0 {
-			l.SetLoadBalancerPort(int64(lp))
-		} else if p := data["protocol"].(string); p == "HTTP" {
-			l.SetLoadBalancerPort(int64(80))
-		} else if p := data["protocol"].(string); p == "HTTPS" {
-			l.SetLoadBalancerPort(int64(443))
-		} else if p := data["protocol"].(string); p == "FTP" {
-			l.SetLoadBalancerPort(int64(21))
+		if lbPort > 0 {
+			l.SetLoadBalancerPort(lbPort)
 		}
 
 		listeners = append(listeners, l)
@@ -128,81 +118,12 @@ func expandRequestLoadBalancerNames(name string, configured []interface{}) ([]*c
 	return listeners, nil
 }
 
-func expandIPPerm(d interface{}) []*computing.RequestIpPermissionsStruct {
+func expandIPPerm(meta interface{}, d interface{}) []*computing.RequestIpPermissionsStruct {
 	var perms []*computing.RequestIpPermissionsStruct
 	for _, ip := range d.(*schema.Set).List() {
 		perm := &computing.RequestIpPermissionsStruct{}
 		if v, ok := ip.(map[string]interface{}); ok {
-			protocol := strings.ToUpper(v["protocol"].(string))
-			if protocol == "ICMPV6-ALL" {
-				protocol = "ICMPv6-all"
-			}
-			switch protocol {
-			case "HTTP":
-				perm.SetIpProtocol("TCP")
-				perm.SetFromPort(int64(80))
-				perm.SetToPort(int64(80))
-
-			case "HTTPS":
-				perm.SetIpProtocol("TCP")
-				perm.SetFromPort(int64(443))
-				perm.SetToPort(int64(443))
-
-			case "SSH":
-				perm.SetIpProtocol("TCP")
-				perm.SetFromPort(int64(22))
-				perm.SetToPort(int64(22))
-
-			case "RDP":
-				perm.SetIpProtocol("TCP")
-				perm.SetFromPort(int64(3389))
-				perm.SetToPort(int64(3389))
-
-			case "L2TP":
-				perm.SetIpProtocol("UDP")
-				perm.SetFromPort(int64(1701))
-				perm.SetToPort(int64(1701))
-
-			case "TCP":
-				perm.SetIpProtocol(protocol)
-				perm.SetFromPort(int64(v["from_port"].(int)))
-				perm.SetToPort(int64(v["to_port"].(int)))
-
-			case "UDP":
-				perm.SetIpProtocol(protocol)
-				perm.SetFromPort(int64(v["from_port"].(int)))
-				perm.SetToPort(int64(v["to_port"].(int)))
-
-			default:
-				perm.SetIpProtocol(protocol)
-
-			}
-
-			if v["inout"].(string) != "" {
-				perm.SetInOut(v["inout"].(string))
-			}
-
-			if v["description"].(string) != "" {
-				perm.SetDescription(v["description"].(string))
-			}
-
-			if v["cidr_blocks"].(string) != "" {
-				tmp := []*computing.RequestIpRangesStruct {
-					{
-						CidrIp: nifcloud.String(v["cidr_blocks"].(string)),
-					},
-				}
-				perm.SetRequestIpRanges(tmp)
-			}
-			
-			if v["security_groups"].(string) != "" {
-				tmp := []*computing.RequestGroupsStruct {
-					{
-						GroupName: nifcloud.String(v["security_groups"].(string)),
-					},
-				}
-				perm.SetRequestGroups(tmp)
-			}
+			perm = expandIPPermItem(meta, v)
 		}
 		perms = append(perms, perm)
 	}
@@ -210,6 +131,85 @@ func expandIPPerm(d interface{}) []*computing.RequestIpPermissionsStruct {
 	return perms
 }
 
+// expandIPPermItem expands a single rule, the named protocols (HTTP, SSH, ...)
+// become TCP/UDP with their well-known port. A description gets the provider's
+// description prefix like the ones of the other resources.
+func expandIPPermItem(meta interface{}, v map[string]interface{}) *computing.RequestIpPermissionsStruct {
+	perm := &computing.RequestIpPermissionsStruct{}
+	protocol := strings.ToUpper(v["protocol"].(string))
+	if protocol == "ICMPV6-ALL" {
+		protocol = "ICMPv6-all"
+	}
+	switch protocol {
+	case "HTTP":
+		perm.SetIpProtocol("TCP")
+		perm.SetFromPort(int64(80))
+		perm.SetToPort(int64(80))
+
+	case "HTTPS":
+		perm.SetIpProtocol("TCP")
+		perm.SetFromPort(int64(443))
+		perm.SetToPort(int64(443))
+
+	case "SSH":
+		perm.SetIpProtocol("TCP")
+		perm.SetFromPort(int64(22))
+		perm.SetToPort(int64(22))
+
+	case "RDP":
+		perm.SetIpProtocol("TCP")
+		perm.SetFromPort(int64(3389))
+		perm.SetToPort(int64(3389))
+
+	case "L2TP":
+		perm.SetIpProtocol("UDP")
+		perm.SetFromPort(int64(1701))
+		perm.SetToPort(int64(1701))
+
+	case "TCP":
+		perm.SetIpProtocol(protocol)
+		perm.SetFromPort(int64(v["from_port"].(int)))
+		perm.SetToPort(int64(v["to_port"].(int)))
+
+	case "UDP":
+		perm.SetIpProtocol(protocol)
+		perm.SetFromPort(int64(v["from_port"].(int)))
+		perm.SetToPort(int64(v["to_port"].(int)))
+
+	default:
+		perm.SetIpProtocol(protocol)
+
+	}
+
+	if v["inout"].(string) != "" {
+		perm.SetInOut(v["inout"].(string))
+	}
+
+	if v["description"].(string) != "" {
+		perm.Description = withDescriptionPrefix(meta, v["description"].(string))
+	}
+
+	if v["cidr_blocks"].(string) != "" {
+		tmp := []*computing.RequestIpRangesStruct {
+			{
+				CidrIp: nifcloud.String(v["cidr_blocks"].(string)),
+			},
+		}
+		perm.SetRequestIpRanges(tmp)
+	}
+	
+	if v["security_groups"].(string) != "" {
+		tmp := []*computing.RequestGroupsStruct {
+			{
+				GroupName: nifcloud.String(v["security_groups"].(string)),
+			},
+		}
+		perm.SetRequestGroups(tmp)
+	}
+
+	return perm
+}
+
 // Expands an array of String Instance IDs into a []Instances
 func expandInstanceString(list []interface{}) []*computing.RequestInstancesStruct {
 	result := make([]*computing.RequestInstancesStruct, 0, len(list))
@@ -253,6 +253,30 @@ func expandDeleteFilter(list []interface{}) []*computing.RequestIPAddressesStruc
 }
 
 
+// Takes a health_check block and returns ELB API compatible object
+func expandHealthCheck(check map[string]interface{}) (*computing.RequestHealthCheckStruct, error) {
+	interval := check["interval"].(int)
+
+	hc := &computing.RequestHealthCheckStruct{
+		UnhealthyThreshold: nifcloud.Int64(int64(check["unhealthy_threshold"].(int))),
+		Interval:           nifcloud.Int64(int64(interval)),
+		Target:             nifcloud.String(check["target"].(string)),
+	}
+
+	// healthy_threshold and timeout are Optional/Computed, so 0 means "not configured"
+	if v, ok := check["healthy_threshold"].(int); ok && v > 0 {
+		hc.SetHealthyThreshold(int64(v))
+	}
+	if v, ok := check["timeout"].(int); ok && v > 0 {
+		if v >= interval {
+			return nil, fmt.Errorf("health_check timeout (%d) must be less than interval (%d)", v, interval)
+		}
+		hc.SetTimeout(int64(v))
+	}
+
+	return hc, nil
+}
+
 // Flattens an array of Instances into a []string
 func flattenInstances(list []*computing.InstancesMemberItem) []string {
 	result := make([]string, 0, len(list))
@@ -323,9 +347,13 @@ func flattenHealthCheck(check *computing.HealthCheck) []map[string]interface{} {
 
 	chk := make(map[string]interface{})
 	chk["unhealthy_threshold"] = *check.UnhealthyThreshold
-//	chk["healthy_threshold"] = *check.HealthyThreshold
+	if check.HealthyThreshold != nil {
+		chk["healthy_threshold"] = *check.HealthyThreshold
+	}
 	chk["target"] = *check.Target
-//	chk["timeout"] = *check.Timeout
+	if check.Timeout != nil {
+		chk["timeout"] = *check.Timeout
+	}
 	chk["interval"] = *check.Interval
 
 	result = append(result, chk)
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"reflect"
 	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
 )
 
 // Base64Encode encodes data if the input isn't already encoded using base64.StdEncoding.EncodeToString.
@@ -52,3 +54,14 @@ func isResourceTimeoutError(err error) bool {
 	timeoutErr, ok := err.(*resource.TimeoutError)
 	return ok && timeoutErr.LastError == nil
 }
+
+// withDescriptionPrefix returns the description to send to the API, the provider's default_description_prefix in front
+func withDescriptionPrefix(meta interface{}, description string) *string {
+	return nifcloud.String(meta.(*NifcloudClient).defaultDescriptionPrefix + description)
+}
+
+// trimDescriptionPrefix returns the description read from the API without the provider's default_description_prefix,
+// so that the prefix never shows up as a diff
+func trimDescriptionPrefix(meta interface{}, description *string) string {
+	return strings.TrimPrefix(nifcloud.StringValue(description), meta.(*NifcloudClient).defaultDescriptionPrefix)
+}
@@ -3,6 +3,7 @@ package nifcloud
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -160,13 +161,41 @@ func validateVpnConnectionTunnelPreSharedKey(v interface{}, k string) (ws []stri
 }
 
 func validateHeathCheckTarget(v interface{}, k string) (ws []string, errors []error) {
-	// TCP:port | ICMP
-	validFormat := "(ICMP|TCP:[0-9]{1,5})"
-	validFormatConsolidated := "^(" + validFormat + "|)$"
-
-	value := strings.ToUpper(v.(string))
-	if !regexp.MustCompile(validFormatConsolidated).MatchString(value) {
-		errors = append(errors, fmt.Errorf("%q must satisfy the format of \"TCP:port | ICMP\"", k))
+	// TCP:port | ICMP | HTTP:port/path | HTTPS:port/path
+	value := v.(string)
+	if value == "" || strings.ToUpper(value) == "ICMP" {
+		return
+	}
+
+	matches := regexp.MustCompile(`^([A-Za-z]+):([0-9]{1,5})(.*)$`).FindStringSubmatch(value)
+	if matches == nil {
+		errors = append(errors, fmt.Errorf("%q must satisfy the format of \"TCP:port | ICMP | HTTP:port/path | HTTPS:port/path\"", k))
+		return
+	}
+
+	port, _ := strconv.Atoi(matches[2])
+	if port < 1 || port > 65535 {
+		errors = append(errors, fmt.Errorf("%q contains an invalid port %d, valid port is in the range from 1 to 65535", k, port))
+	}
+
+	path := matches[3]
+	switch strings.ToUpper(matches[1]) {
+	case "TCP":
+		if path != "" {
+			errors = append(errors, fmt.Errorf("%q cannot contain a path for TCP health check: %s", k, value))
+		}
+	case "HTTP", "HTTPS":
+		if !strings.HasPrefix(path, "/") {
+			errors = append(errors, fmt.Errorf("%q must contain a path starting with \"/\" for HTTP(S) health check: %s", k, value))
+		}
+		if len(path) > 1024 {
+			errors = append(errors, fmt.Errorf("%q cannot contain a path longer than 1024 characters: %s", k, value))
+		}
+		if !regexp.MustCompile(`^[!-~]*$`).MatchString(path) {
+			errors = append(errors, fmt.Errorf("%q can only contain printable ASCII characters without spaces in the path: %s", k, value))
+		}
+	default:
+		errors = append(errors, fmt.Errorf("%q contains an invalid protocol %q, valid protocols are TCP, ICMP, HTTP or HTTPS", k, matches[1]))
 	}
 	return
 }
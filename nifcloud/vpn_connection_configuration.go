@@ -0,0 +1,319 @@
+package nifcloud
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"text/template"
+
+	"github.com/shztki/nifcloud-sdk-go/nifcloud"
+	"github.com/shztki/nifcloud-sdk-go/service/computing"
+)
+
+// vpnConnectionConfiguration holds what the customer side device needs
+// to connect to a NIFCLOUD VPN gateway.
+type vpnConnectionConfiguration struct {
+	VpnConnectionID string
+	Type            string
+
+	// NIFCLOUD side
+	VpnGatewayAddress string
+	VpnGatewayNetwork string
+
+	// Customer side
+	CustomerGatewayAddress string
+	CustomerLanNetwork     string
+
+	PreSharedKey        string
+	DH                  int64
+	IkeVersion          string
+	IkeLifetime         int64
+	EspLifetime         int64
+	EncryptionAlgorithm string
+	HashAlgorithm       string
+	Mtu                 string
+
+	Tunnel map[string]interface{}
+}
+
+// newVpnConnectionConfiguration collects the addresses of both gateways
+// on top of the ipsec/tunnel values of the connection.
+func newVpnConnectionConfiguration(conn *computing.Computing, vpnConnection *computing.VpnConnectionSetItem) (*vpnConnectionConfiguration, error) {
+	c := &vpnConnectionConfiguration{
+		VpnConnectionID: nifcloud.StringValue(vpnConnection.VpnConnectionId),
+		Type:            nifcloud.StringValue(vpnConnection.Type),
+	}
+
+	if ipsec := vpnConnection.NiftyIpsecConfiguration; ipsec != nil {
+		c.PreSharedKey = nifcloud.StringValue(ipsec.PreSharedKey)
+		c.DH = nifcloud.Int64Value(ipsec.DiffieHellmanGroup)
+		c.IkeVersion = nifcloud.StringValue(ipsec.InternetKeyExchange)
+		c.IkeLifetime = nifcloud.Int64Value(ipsec.InternetKeyExchangeLifetime)
+		c.EspLifetime = nifcloud.Int64Value(ipsec.EncapsulatingSecurityPayloadLifetime)
+		c.EncryptionAlgorithm = nifcloud.StringValue(ipsec.EncryptionAlgorithm)
+		c.HashAlgorithm = nifcloud.StringValue(ipsec.HashingAlgorithm)
+		c.Mtu = nifcloud.StringValue(ipsec.Mtu)
+	}
+	if vpnConnection.NiftyTunnel != nil {
+		if tunnels := tunnelToMapList(vpnConnection.NiftyTunnel); len(tunnels) > 0 {
+			c.Tunnel = tunnels[0]
+		}
+	}
+
+	vgwResp, err := conn.DescribeVpnGateways(&computing.DescribeVpnGatewaysInput{
+		Filter: []*computing.RequestFilterStruct{{
+			Name:         nifcloud.String("vpn-gateway-id"),
+			RequestValue: []*string{vpnConnection.VpnGatewayId},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error finding VpnGateway: %s", err)
+	}
+	if len(vgwResp.VpnGatewaySet) > 0 {
+		vpnGateway := vgwResp.VpnGatewaySet[0]
+		c.VpnGatewayAddress = nifcloud.StringValue(vpnGateway.IpAddress)
+
+		for _, ni := range vpnGateway.NetworkInterfaceSet {
+			networkID := nifcloud.StringValue(ni.NetworkId)
+			if networkID == "" || networkID == "net-COMMON_GLOBAL" || networkID == "net-COMMON_PRIVATE" {
+				continue
+			}
+			lanResp, err := conn.NiftyDescribePrivateLans(&computing.NiftyDescribePrivateLansInput{
+				NetworkId: []*string{nifcloud.String(networkID)},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Error finding private lan (%s): %s", networkID, err)
+			}
+			if len(lanResp.PrivateLanSet) > 0 {
+				c.VpnGatewayNetwork = nifcloud.StringValue(lanResp.PrivateLanSet[0].CidrBlock)
+			}
+			break
+		}
+	}
+
+	cgwResp, err := conn.DescribeCustomerGateways(&computing.DescribeCustomerGatewaysInput{
+		Filter: []*computing.RequestFilterStruct{{
+			Name:         nifcloud.String("customer-gateway-id"),
+			RequestValue: []*string{vpnConnection.CustomerGatewayId},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error finding CustomerGateway: %s", err)
+	}
+	if len(cgwResp.CustomerGatewaySet) > 0 {
+		customerGateway := cgwResp.CustomerGatewaySet[0]
+		c.CustomerGatewayAddress = nifcloud.StringValue(customerGateway.IpAddress)
+		c.CustomerLanNetwork = nifcloud.StringValue(customerGateway.NiftyLanSideCidrBlock)
+	}
+
+	return c, nil
+}
+
+// vpnConnectionL2tpv3Unsupported are the notes put in place of the device
+// configuration for an L2TPv3 connection by the vendors whose template only
+// sets up IPsec, so the output says so instead of leaving the vendor out.
+var vpnConnectionL2tpv3Unsupported = map[string]string{
+	"cisco_ios":  "! NIFCLOUD VPN connection %s (%s)\n!\n! L2TPv3 is not covered by the cisco_ios configuration, see strongswan for the tunnel parameters\n",
+	"yamaha_rtx": "# NIFCLOUD VPN connection %s (%s)\n#\n# L2TPv3 is not covered by the yamaha_rtx configuration, see strongswan for the tunnel parameters\n",
+}
+
+// render returns the device configuration for every vendor
+func (c *vpnConnectionConfiguration) render() (map[string]string, error) {
+	result := make(map[string]string, len(vpnConnectionConfigurationTemplates))
+	for vendor, tmpl := range vpnConnectionConfigurationTemplates {
+		if note, ok := vpnConnectionL2tpv3Unsupported[vendor]; ok && c.Tunnel != nil {
+			log.Printf("[WARN] %s configuration doesn't support L2TPv3, VPN connection (%s) gets a note only", vendor, c.VpnConnectionID)
+			result[vendor] = fmt.Sprintf(note, c.VpnConnectionID, c.Type)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, c); err != nil {
+			return nil, fmt.Errorf("Error rendering %s configuration for VPN connection (%s): %s", vendor, c.VpnConnectionID, err)
+		}
+		result[vendor] = buf.String()
+	}
+	return result, nil
+}
+
+// Algorithm names per vendor, keyed by the NIFCLOUD value
+var vpnConnectionAlgorithmNames = map[string]map[string]string{
+	"cisco_ike_encryption":   {"AES128": "aes 128", "AES256": "aes 256", "3DES": "3des"},
+	"cisco_ikev2_encryption": {"AES128": "aes-cbc-128", "AES256": "aes-cbc-256", "3DES": "3des"},
+	"cisco_ike_hash":         {"SHA1": "sha", "MD5": "md5", "SHA256": "sha256", "SHA384": "sha384", "SHA512": "sha512"},
+	"cisco_esp_encryption":   {"AES128": "esp-aes 128", "AES256": "esp-aes 256", "3DES": "esp-3des"},
+	"cisco_esp_hash":         {"SHA1": "esp-sha-hmac", "MD5": "esp-md5-hmac", "SHA256": "esp-sha256-hmac", "SHA384": "esp-sha384-hmac", "SHA512": "esp-sha512-hmac"},
+	"cisco_ikev2_hash":       {"SHA1": "sha1", "MD5": "md5", "SHA256": "sha256", "SHA384": "sha384", "SHA512": "sha512"},
+	"rtx_encryption":         {"AES128": "aes-cbc", "AES256": "aes256-cbc", "3DES": "3des-cbc"},
+	"rtx_ike_hash":           {"SHA1": "sha", "MD5": "md5", "SHA256": "sha256", "SHA384": "sha384", "SHA512": "sha512"},
+	"rtx_esp_hash":           {"SHA1": "sha-hmac", "MD5": "md5-hmac", "SHA256": "sha256-hmac", "SHA384": "sha384-hmac", "SHA512": "sha512-hmac"},
+	"strongswan":             {"AES128": "aes128", "AES256": "aes256", "3DES": "3des", "SHA1": "sha1", "MD5": "md5", "SHA256": "sha256", "SHA384": "sha384", "SHA512": "sha512"},
+}
+
+// Diffie-Hellman group names, RTX and strongSwan share them
+var vpnConnectionDHGroupNames = map[int64]string{
+	1: "modp768", 2: "modp1024", 5: "modp1536", 14: "modp2048", 15: "modp3072", 16: "modp4096",
+	17: "modp6144", 18: "modp8192", 19: "ecp256", 20: "ecp384", 21: "ecp521",
+	22: "modp1024s160", 23: "modp2048s224", 24: "modp2048s256", 25: "ecp192", 26: "ecp224",
+}
+
+var vpnConnectionConfigurationFuncs = template.FuncMap{
+	"alg": func(kind, v string) string {
+		if name, ok := vpnConnectionAlgorithmNames[kind][strings.ToUpper(v)]; ok {
+			return name
+		}
+		return strings.ToLower(v)
+	},
+	"group": func(dh int64) string {
+		if name, ok := vpnConnectionDHGroupNames[dh]; ok {
+			return name
+		}
+		return fmt.Sprintf("group%d", dh)
+	},
+	"ikev2": func(v string) bool {
+		return strings.EqualFold(v, "IKEv2")
+	},
+	"wildcard": func(cidr string) string {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return cidr
+		}
+		wildcard := make(net.IP, len(ipnet.Mask))
+		for i, b := range ipnet.Mask {
+			wildcard[i] = ^b
+		}
+		return fmt.Sprintf("%s %s", ipnet.IP, wildcard)
+	},
+	"udp": func(encapsulation interface{}) bool {
+		return strings.EqualFold(fmt.Sprintf("%v", encapsulation), "UDP")
+	},
+	"default": func(v, placeholder string) string {
+		if v == "" {
+			return placeholder
+		}
+		return v
+	},
+}
+
+var vpnConnectionConfigurationTemplates = map[string]*template.Template{
+	"cisco_ios": template.Must(template.New("cisco_ios").Funcs(vpnConnectionConfigurationFuncs).Parse(`! NIFCLOUD VPN connection {{.VpnConnectionID}} ({{.Type}})
+!
+{{- if ikev2 .IkeVersion}}
+crypto ikev2 proposal nifcloud-proposal
+ encryption {{alg "cisco_ikev2_encryption" .EncryptionAlgorithm}}
+ integrity {{alg "cisco_ikev2_hash" .HashAlgorithm}}
+ group {{.DH}}
+!
+crypto ikev2 policy nifcloud-policy
+ proposal nifcloud-proposal
+!
+crypto ikev2 keyring nifcloud-keyring
+ peer nifcloud
+  address {{.VpnGatewayAddress}}
+  pre-shared-key {{.PreSharedKey}}
+!
+crypto ikev2 profile nifcloud-profile
+ match identity remote address {{.VpnGatewayAddress}} 255.255.255.255
+ authentication remote pre-share
+ authentication local pre-share
+ keyring local nifcloud-keyring
+ lifetime {{.IkeLifetime}}
+{{- else}}
+crypto isakmp policy 10
+ encryption {{alg "cisco_ike_encryption" .EncryptionAlgorithm}}
+ hash {{alg "cisco_ike_hash" .HashAlgorithm}}
+ authentication pre-share
+ group {{.DH}}
+ lifetime {{.IkeLifetime}}
+!
+crypto isakmp key {{.PreSharedKey}} address {{.VpnGatewayAddress}}
+{{- end}}
+!
+crypto ipsec transform-set nifcloud-transform {{alg "cisco_esp_encryption" .EncryptionAlgorithm}} {{alg "cisco_esp_hash" .HashAlgorithm}}
+ mode tunnel
+!
+ip access-list extended nifcloud-vpn
+ permit ip {{default .CustomerLanNetwork "CUSTOMER_LAN_CIDR" | wildcard}} {{default .VpnGatewayNetwork "NIFCLOUD_LAN_CIDR" | wildcard}}
+!
+crypto map nifcloud-map 10 ipsec-isakmp
+ set peer {{.VpnGatewayAddress}}
+ set transform-set nifcloud-transform
+ set pfs {{printf "group%d" .DH}}
+ set security-association lifetime seconds {{.EspLifetime}}
+{{- if ikev2 .IkeVersion}}
+ set ikev2-profile nifcloud-profile
+{{- end}}
+ match address nifcloud-vpn
+!
+! apply "crypto map nifcloud-map" to the interface that owns {{.CustomerGatewayAddress}}
+! and set "ip tcp adjust-mss" according to MTU {{.Mtu}}
+`)),
+
+	"yamaha_rtx": template.Must(template.New("yamaha_rtx").Funcs(vpnConnectionConfigurationFuncs).Parse(`# NIFCLOUD VPN connection {{.VpnConnectionID}} ({{.Type}})
+#
+tunnel select 1
+ ipsec tunnel 101
+  ipsec sa policy 101 1 esp {{alg "rtx_encryption" .EncryptionAlgorithm}} {{alg "rtx_esp_hash" .HashAlgorithm}}
+  ipsec ike version 1 {{if ikev2 .IkeVersion}}2{{else}}1{{end}}
+  ipsec ike duration ipsec-sa 1 {{.EspLifetime}}
+  ipsec ike duration isakmp-sa 1 {{.IkeLifetime}}
+  ipsec ike encryption 1 {{alg "rtx_encryption" .EncryptionAlgorithm}}
+  ipsec ike group 1 {{group .DH}}
+  ipsec ike hash 1 {{alg "rtx_ike_hash" .HashAlgorithm}}
+  ipsec ike keepalive use 1 on
+  ipsec ike local address 1 {{.CustomerGatewayAddress}}
+  ipsec ike local id 1 {{default .CustomerLanNetwork "CUSTOMER_LAN_CIDR"}}
+  ipsec ike pfs 1 on
+  ipsec ike pre-shared-key 1 text {{.PreSharedKey}}
+  ipsec ike remote address 1 {{.VpnGatewayAddress}}
+  ipsec ike remote id 1 {{default .VpnGatewayNetwork "NIFCLOUD_LAN_CIDR"}}
+ ip tunnel mtu {{default .Mtu "1500"}}
+ ip tunnel tcp mss limit auto
+ tunnel enable 1
+#
+ip route {{default .VpnGatewayNetwork "NIFCLOUD_LAN_CIDR"}} gateway tunnel 1
+ipsec auto refresh on
+`)),
+
+	"strongswan": template.Must(template.New("strongswan").Funcs(vpnConnectionConfigurationFuncs).Parse(`# NIFCLOUD VPN connection {{.VpnConnectionID}} ({{.Type}})
+#
+# /etc/ipsec.conf
+conn nifcloud
+  keyexchange={{if ikev2 .IkeVersion}}ikev2{{else}}ikev1{{end}}
+  authby=secret
+  left=%defaultroute
+  leftid={{.CustomerGatewayAddress}}
+  right={{.VpnGatewayAddress}}
+  rightid={{.VpnGatewayAddress}}
+{{- with .Tunnel}}
+  type=transport
+  leftprotoport={{if udp (index . "encapsulation")}}udp/{{index . "destination_port"}}{{else}}115{{end}}
+  rightprotoport={{if udp (index . "encapsulation")}}udp/{{index . "source_port"}}{{else}}115{{end}}
+{{- else}}
+  leftsubnet={{default .CustomerLanNetwork "CUSTOMER_LAN_CIDR"}}
+  rightsubnet={{default .VpnGatewayNetwork "NIFCLOUD_LAN_CIDR"}}
+{{- end}}
+  ike={{alg "strongswan" .EncryptionAlgorithm}}-{{alg "strongswan" .HashAlgorithm}}-{{group .DH}}!
+  esp={{alg "strongswan" .EncryptionAlgorithm}}-{{alg "strongswan" .HashAlgorithm}}-{{group .DH}}!
+  ikelifetime={{.IkeLifetime}}s
+  lifetime={{.EspLifetime}}s
+  dpdaction=restart
+  auto=start
+#
+# /etc/ipsec.secrets
+{{.CustomerGatewayAddress}} {{.VpnGatewayAddress}} : PSK "{{.PreSharedKey}}"
+{{- with $t := .Tunnel}}
+#
+# L2TPv3 tunnel (iproute2), bridge l2tpeth0 with the LAN side interface
+ip l2tp add tunnel tunnel_id {{index $t "peer_tunnel_id"}} peer_tunnel_id {{index $t "tunnel_id"}} \
+{{- if udp (index $t "encapsulation")}}
+  encap udp local {{$.CustomerGatewayAddress}} remote {{$.VpnGatewayAddress}} udp_sport {{index $t "destination_port"}} udp_dport {{index $t "source_port"}}
+{{- else}}
+  encap ip local {{$.CustomerGatewayAddress}} remote {{$.VpnGatewayAddress}}
+{{- end}}
+ip l2tp add session name l2tpeth0 tunnel_id {{index $t "peer_tunnel_id"}} session_id {{index $t "peer_session_id"}} peer_session_id {{index $t "session_id"}}
+ip link set l2tpeth0 up mtu {{default $.Mtu "1500"}}
+{{- end}}
+`)),
+}